@@ -0,0 +1,89 @@
+package progress
+
+import (
+	"context"
+	"time"
+)
+
+// sender coordinates the background goroutine used by Options.Async.
+type sender struct {
+	signal chan struct{} // Receives a value when there's a new position to send
+	done   chan struct{} // Closed when the goroutine has stopped
+	err    error         // The last error sending a message. Guarded by Progress.mu.
+}
+
+func newSender() *sender {
+	return &sender{
+		signal: make(chan struct{}, 1),
+		done:   make(chan struct{}),
+	}
+}
+
+// wake tells the background goroutine there's something new to send.
+func (s *sender) wake() {
+	select {
+	case s.signal <- struct{}{}:
+	default: // The sender already knows there's something new
+	}
+}
+
+// sendLoop sends the latest pending position whenever there's a new one,
+// waiting MinInterval between messages. Positions that arrive in the mean
+// time replace the pending one. After Close the latest position is sent one
+// last time.
+func (p *Progress) sendLoop() {
+	s := p.async
+	defer close(s.done)
+
+	for {
+		var quit bool
+		select {
+		case <-s.signal:
+		case <-p.closed:
+			quit = true
+		}
+
+		p.mu.Lock()
+		wait := p.Opts.MinInterval - time.Since(p.lastSent)
+		p.mu.Unlock()
+
+		if wait > 0 {
+			time.Sleep(wait)
+		}
+		p.flush()
+
+		if quit {
+			return
+		}
+	}
+}
+
+// flush sends the pending position.
+func (p *Progress) flush() {
+	p.sendMu.Lock()
+	defer p.sendMu.Unlock()
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if err := p.update(context.Background(), p.pending, p.total()); err != nil {
+		p.async.err = err
+	}
+}
+
+// Close stops the background goroutines used by Options.Async and
+// Options.Refresh and removes the progress bar from Running. The async sender
+// sends the latest position first and Close returns the last error it ran
+// into. It's safe to call Close more than once.
+func (p *Progress) Close() error {
+	p.closeOnce.Do(func() { close(p.closed) })
+	unregister(p)
+	if p.async == nil {
+		return nil
+	}
+
+	<-p.async.done
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.async.err
+}
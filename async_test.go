@@ -0,0 +1,86 @@
+package progress_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/sfreiberg/progress"
+)
+
+// slowSink takes a while for every message, like a real chat system would.
+type slowSink struct {
+	mu sync.Mutex
+	memSink
+}
+
+func (s *slowSink) Post(ctx context.Context, msg *progress.Message) (string, error) {
+	time.Sleep(20 * time.Millisecond)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.memSink.Post(ctx, msg)
+}
+
+func (s *slowSink) Update(ctx context.Context, id string, msg *progress.Message) error {
+	time.Sleep(20 * time.Millisecond)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.memSink.Update(ctx, id, msg)
+}
+
+func TestAsync(t *testing.T) {
+	sink := &slowSink{}
+	pbar := newProgress(t, sink, progress.WithAsync(), progress.WithTotal(10000))
+
+	start := time.Now()
+	for i := 1; i <= 9999; i++ {
+		if err := pbar.Update(i); err != nil {
+			t.Fatalf("Error updating progress bar: %s", err)
+		}
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("Expected updates not to wait on the sink, took %s", elapsed)
+	}
+
+	if err := pbar.Close(); err != nil {
+		t.Fatalf("Error closing progress bar: %s", err)
+	}
+
+	sink.mu.Lock()
+	defer sink.mu.Unlock()
+
+	msgs := append(sink.posts, sink.updates...)
+	if len(msgs) == 0 || len(msgs) > 50 {
+		t.Fatalf("Expected intermediate positions to be dropped, got %d messages", len(msgs))
+	}
+	if last := msgs[len(msgs)-1]; last.Pos != 9999 {
+		t.Errorf("Expected Close to send the latest position, got %d", last.Pos)
+	}
+}
+
+func TestAsyncCloseWaitsForInterval(t *testing.T) {
+	sink := &slowSink{}
+	pbar, err := progress.NewWithSink(sink, progress.WithAsync(), progress.WithMinInterval(100*time.Millisecond))
+	if err != nil {
+		t.Fatalf("Error creating progress bar: %s", err)
+	}
+
+	for _, pos := range []int{10, 20} {
+		if err := pbar.Update(pos); err != nil {
+			t.Fatalf("Error updating progress bar: %s", err)
+		}
+		time.Sleep(40 * time.Millisecond)
+	}
+	if err := pbar.Close(); err != nil {
+		t.Fatalf("Error closing progress bar: %s", err)
+	}
+
+	sink.mu.Lock()
+	defer sink.mu.Unlock()
+
+	msgs := append(sink.posts, sink.updates...)
+	if last := msgs[len(msgs)-1]; last.Pct != 20 {
+		t.Errorf("Expected Close to send 20%%, got %d%%", last.Pct)
+	}
+}
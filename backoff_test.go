@@ -0,0 +1,31 @@
+package progress
+
+import (
+	"testing"
+	"time"
+)
+
+func TestBackoff(t *testing.T) {
+	tests := []struct {
+		name    string
+		backoff time.Duration
+		max     time.Duration
+		attempt int
+		want    time.Duration // The wait before jitter
+	}{
+		{"first attempt", time.Second, 10 * time.Second, 1, time.Second},
+		{"doubles", time.Second, 10 * time.Second, 3, 4 * time.Second},
+		{"capped", time.Second, 10 * time.Second, 6, 10 * time.Second},
+		{"no max", time.Second, 0, 6, 32 * time.Second},
+		{"no backoff", 0, 0, 3, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := &Progress{Opts: &Options{RetryBackoff: tt.backoff, MaxRetryBackoff: tt.max}}
+			if got := p.backoff(tt.attempt); got < tt.want/2 || got > tt.want {
+				t.Errorf("Expected a backoff between %s and %s, got %s", tt.want/2, tt.want, got)
+			}
+		})
+	}
+}
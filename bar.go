@@ -0,0 +1,111 @@
+package progress
+
+import "strings"
+
+// State is the state of a task that a Renderer draws the progress bar for.
+type State struct {
+	Pos      int64     // Position passed to Progress.Update
+	Total    int64     // Total possible units
+	Pct      int       // Percent complete
+	Percent  float64   // Percent complete with Options.Precision decimals
+	Width    int       // Options.Width
+	Complete bool      // Whether or not the task has reached 100%
+	Failed   bool      // Whether or not Progress.Fail was called
+	Overdue  bool      // Whether or not the deadline has passed
+	Counts   Counts    // Items counted with Progress.IncSuccess, IncFailed and IncSkipped
+	Segments []Segment // Segments set with Progress.SetSegments
+}
+
+// Renderer draws the progress bar shown in messages as {{.ProgBar}}.
+type Renderer interface {
+	Render(state State) string
+}
+
+// RendererFunc is a function that implements Renderer.
+type RendererFunc func(state State) string
+
+// Render calls f(state).
+func (f RendererFunc) Render(state State) string {
+	return f(state)
+}
+
+// renderer returns Options.Renderer or the built in bar if it isn't set.
+func (p *Progress) renderer() Renderer {
+	if p.Opts.Renderer != nil {
+		return p.Opts.Renderer
+	}
+	return RendererFunc(p.draw)
+}
+
+// draw is the built in Renderer. It draws the bar with FailFill or
+// OverdueFill when the task failed or is overdue, otherwise with sub blocks,
+// segments or counts if they're used, and with Fill (or Fills) if not.
+func (p *Progress) draw(s State) string {
+	switch {
+	case s.Failed:
+		return p.drawBar(s.Pct, p.Opts.FailFill)
+	case s.Overdue && p.Opts.OverdueFill != "":
+		return p.drawBar(s.Pct, p.Opts.OverdueFill)
+	case p.Opts.SubBlocks:
+		return p.drawSubBlocks(s.Pct)
+	case s.Segments != nil:
+		return p.drawSegments(s.Segments, s.Total)
+	case p.Opts.CountBar:
+		return p.drawSegments(p.countSegments(s.Counts), s.Total)
+	default:
+		return p.drawBar(s.Pct, p.fill(s.Pct))
+	}
+}
+
+// Rounding decides how partly filled cells of the progress bar are drawn.
+type Rounding int
+
+const (
+	RoundDown    Rounding = iota // Only cells that are completely done are filled
+	RoundNearest                 // Cells that are at least half done are filled
+	RoundUp                      // Cells that are started are filled
+)
+
+// cells returns how many of width cells are filled at pct.
+func (r Rounding) cells(pct, width int) int {
+	switch r {
+	case RoundNearest:
+		return (pct*width + 50) / 100
+	case RoundUp:
+		return (pct*width + 99) / 100
+	default:
+		return pct * width / 100
+	}
+}
+
+// drawBar draws a bar of Options.Width cells at pct, filling the cells that
+// are done with fill and the rest with Options.Empty.
+func (p *Progress) drawBar(pct int, fill string) string {
+	pct = min(max(pct, 0), 100)
+	cells := p.Opts.Rounding.cells(pct, p.Opts.Width)
+
+	bar := strings.Repeat(fill, cells)
+	bar += strings.Repeat(p.Opts.Empty, p.Opts.Width-cells)
+
+	return p.Opts.BarLeft + bar + p.Opts.BarRight
+}
+
+// partialBlocks are the blocks filling one to seven eighths of a cell.
+var partialBlocks = []string{"▏", "▎", "▍", "▌", "▋", "▊", "▉"}
+
+// drawSubBlocks draws a bar at pct with full blocks and a partial block for
+// the last cell that's only partly done.
+func (p *Progress) drawSubBlocks(pct int) string {
+	eighths := pct * p.Opts.Width * 8 / 100
+	full, partial := eighths/8, eighths%8
+
+	bar := strings.Repeat("█", full)
+	empty := p.Opts.Width - full
+	if partial > 0 {
+		bar += partialBlocks[partial-1]
+		empty--
+	}
+	bar += strings.Repeat(p.Opts.Empty, empty)
+
+	return p.Opts.BarLeft + bar + p.Opts.BarRight
+}
@@ -0,0 +1,57 @@
+package progress
+
+import "testing"
+
+func TestDrawBar(t *testing.T) {
+	tests := []struct {
+		width    int
+		pct      int
+		rounding Rounding
+		bar      string
+	}{
+		{10, 0, RoundDown, "----------"},
+		{10, 50, RoundDown, "#####-----"},
+		{10, 100, RoundDown, "##########"},
+		{5, 39, RoundDown, "#----"},
+		{5, 40, RoundDown, "##---"},
+		{5, 99, RoundDown, "####-"},
+		{20, 50, RoundDown, "##########----------"},
+		{20, 99, RoundDown, "###################-"},
+		{40, 1, RoundDown, "----------------------------------------"},
+		{40, 3, RoundDown, "#---------------------------------------"},
+		{40, 100, RoundDown, "########################################"},
+		{1, 99, RoundDown, "-"},
+		{1, 100, RoundDown, "#"},
+		{5, 30, RoundNearest, "##---"},
+		{5, 29, RoundNearest, "#----"},
+		{5, 1, RoundUp, "#----"},
+		{5, 0, RoundUp, "-----"},
+		{3, 34, RoundUp, "##-"},
+	}
+
+	for _, test := range tests {
+		opts := DefaultOptions("test")
+		opts.Width = test.width
+		opts.Fill = "#"
+		opts.Empty = "-"
+		opts.Rounding = test.rounding
+		p := &Progress{Opts: opts}
+
+		if bar := p.drawBar(test.pct, opts.Fill); bar != test.bar {
+			t.Errorf("Expected width %d at %d%% with rounding %d to be %q, got %q", test.width, test.pct, test.rounding, test.bar, bar)
+		}
+	}
+}
+
+func TestDrawBarEmoji(t *testing.T) {
+	// Fills made of more than one rune, like emoji with variation selectors,
+	// still take one cell each
+	opts := DefaultOptions("test")
+	opts.Fill = "❤️"
+	opts.Empty = "🤍"
+	p := &Progress{Opts: opts}
+
+	if bar := p.drawBar(30, opts.Fill); bar != "❤️❤️❤️🤍🤍🤍🤍🤍🤍🤍" {
+		t.Errorf("Expected 3 hearts and 7 empty cells, got %q", bar)
+	}
+}
@@ -0,0 +1,43 @@
+package progress
+
+import "sort"
+
+// Workspace is where a Broadcaster posts in a slack workspace.
+type Workspace struct {
+	Token   string // Bot token for the workspace
+	Channel string // Channel ID or #name to post to
+}
+
+// Broadcaster is a Sink that mirrors one progress bar into several slack
+// workspaces, each with its own token. Errors are prefixed with the name of
+// the workspace that returned them.
+type Broadcaster struct {
+	multiSink
+}
+
+// NewBroadcaster creates a Broadcaster that posts to every workspace in
+// workspaces, which maps a name for the workspace to its token and channel.
+// opts are used by every workspace. If opts is nil then DefaultOptions are
+// used.
+func NewBroadcaster(workspaces map[string]Workspace, opts *Options) *Broadcaster {
+	names := make([]string, 0, len(workspaces))
+	for name := range workspaces {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	b := &Broadcaster{multiSink{names: names, ids: make([]string, len(names))}}
+	for _, name := range names {
+		ws := workspaces[name]
+		b.sinks = append(b.sinks, NewSlackSink(ws.Token, ws.Channel, opts))
+	}
+	return b
+}
+
+// NewBroadcast creates a new progress bar that's mirrored into every
+// workspace in workspaces. Progress is created with DefaultOptions customized
+// by opts.
+func NewBroadcast(workspaces map[string]Workspace, opts ...Option) (*Progress, error) {
+	o := buildOptions(opts)
+	return NewWithSink(NewBroadcaster(workspaces, o), o)
+}
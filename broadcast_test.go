@@ -0,0 +1,42 @@
+package progress
+
+import (
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func TestBroadcaster(t *testing.T) {
+	opts := DefaultOptions("release")
+	b := NewBroadcaster(map[string]Workspace{
+		"internal": {Token: "xoxb-internal", Channel: "C1"},
+		"customer": {Token: "xoxb-customer", Channel: "C2"},
+	}, opts)
+	if len(b.names) != 2 || b.names[0] != "customer" || b.names[1] != "internal" {
+		t.Fatalf("Expected the workspaces in order of name, got %v", b.names)
+	}
+
+	var posts []string
+	customer, done := newTestSlackSink(t, opts, func(method string, form url.Values) string {
+		return `{"ok":false,"error":"channel_not_found"}`
+	})
+	defer done()
+	internal, done := newTestSlackSink(t, opts, func(method string, form url.Values) string {
+		posts = append(posts, method)
+		return `{"ok":true,"channel":"C1","ts":"1.2"}`
+	})
+	defer done()
+	b.sinks = []Sink{customer, internal}
+
+	pbar, err := NewWithSink(b, WithMinInterval(0))
+	if err != nil {
+		t.Fatalf("Error creating progress bar: %s", err)
+	}
+	err = pbar.Update(50)
+	if err == nil || !strings.HasPrefix(err.Error(), "customer: ") {
+		t.Errorf("Expected an error from the customer workspace, got %v", err)
+	}
+	if len(posts) != 1 || posts[0] != "chat.postMessage" {
+		t.Errorf("Expected the internal workspace to be posted to, got %v", posts)
+	}
+}
@@ -0,0 +1,66 @@
+package progress
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/slack-go/slack"
+)
+
+// channelIDs caches channel names that have been resolved to IDs by token and
+// name so that every progress bar doesn't have to list all the channels. The
+// token is hashed with channelKey so it isn't kept in memory in plain text.
+var channelIDs sync.Map
+
+// channelKey is the key of channel name in channelIDs for token.
+func channelKey(token, name string) [sha256.Size]byte {
+	return sha256.Sum256([]byte(token + "\x00" + name))
+}
+
+// resolveChannel replaces a channel name starting with # with the ID of the
+// channel. Names are looked up with conversations.list, which needs the
+// channels:read scope.
+func (s *slackSink) resolveChannel(ctx context.Context) error {
+	if !strings.HasPrefix(s.channel, "#") {
+		return nil
+	}
+
+	name := strings.TrimPrefix(s.channel, "#")
+	key := channelKey(s.token, name)
+	if id, ok := channelIDs.Load(key); ok {
+		s.channel = id.(string)
+		return nil
+	}
+
+	params := &slack.GetConversationsParameters{
+		ExcludeArchived: true,
+		Limit:           1000,
+		Types:           []string{"public_channel", "private_channel"},
+	}
+	for {
+		channels, cursor, err := s.client.GetConversationsContext(ctx, params)
+		if err != nil {
+			return slackError(err)
+		}
+
+		for _, channel := range channels {
+			if channel.Name != name {
+				continue
+			}
+			if !channel.IsMember {
+				return fmt.Errorf("%w #%s, invite the bot with /invite", ErrNotInChannel, name)
+			}
+			channelIDs.Store(key, channel.ID)
+			s.channel = channel.ID
+			return nil
+		}
+
+		if cursor == "" {
+			return fmt.Errorf("%w: #%s", ErrChannelNotFound, name)
+		}
+		params.Cursor = cursor
+	}
+}
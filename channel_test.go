@@ -0,0 +1,60 @@
+package progress
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"testing"
+)
+
+func TestResolveChannel(t *testing.T) {
+	// Start without cached channels when the test runs more than once
+	channelIDs.Range(func(key, _ any) bool {
+		channelIDs.Delete(key)
+		return true
+	})
+
+	var lists int
+	handler := func(method string, form url.Values) string {
+		switch method {
+		case "conversations.list":
+			lists++
+			if form.Get("cursor") == "" {
+				return `{"ok":true,"channels":[{"id":"C1","name":"general","is_member":true}],"response_metadata":{"next_cursor":"next"}}`
+			}
+			return `{"ok":true,"channels":[{"id":"C2","name":"resolve-deploys","is_member":true},{"id":"C3","name":"resolve-private","is_member":false}]}`
+		default:
+			if form.Get("channel") != "C2" {
+				t.Errorf("Expected a message to C2, got %v", form)
+			}
+			return `{"ok":true,"channel":"C2","ts":"1.2"}`
+		}
+	}
+
+	for i := 0; i < 2; i++ {
+		sink, done := newTestSlackSink(t, DefaultOptions("deploy"), handler)
+		sink.channel = "#resolve-deploys"
+		if _, err := sink.Post(context.Background(), &Message{Text: "deploy"}); err != nil {
+			t.Fatalf("Error posting: %s", err)
+		}
+		done()
+	}
+	if lists != 2 {
+		t.Errorf("Expected the channel to be listed once and then cached, got %d requests", lists)
+	}
+	channelIDs.Range(func(key, _ any) bool {
+		if _, ok := key.(string); ok {
+			t.Errorf("Expected the token to be hashed, got key %q", key)
+		}
+		return true
+	})
+
+	for channel, want := range map[string]error{"#resolve-private": ErrNotInChannel, "#resolve-missing": ErrChannelNotFound} {
+		sink, done := newTestSlackSink(t, DefaultOptions("deploy"), handler)
+		sink.channel = channel
+		if _, err := sink.Post(context.Background(), &Message{Text: "deploy"}); !errors.Is(err, want) {
+			t.Errorf("Expected %v posting to %s, got %v", want, channel, err)
+		}
+		done()
+	}
+}
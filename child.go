@@ -0,0 +1,133 @@
+package progress
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrInvalidWeight is returned by NewChild when the weight isn't positive.
+var ErrInvalidWeight = errors.New("Invalid weight")
+
+// child is the part of a parent's progress that's made up by a child progress
+// bar.
+type child struct {
+	task   string
+	weight float64
+	done   float64 // How much of the child is done, from 0 to 1
+}
+
+// NewChild creates a child progress bar for a phase of the task. The child
+// doesn't post a message of its own. Instead the parent's position is derived
+// from all of its children, each counting for its weight of the total, and the
+// parent shows the child that was updated last as its phase. If a child fails
+// so does the parent. Don't update the parent directly once it has children.
+func (p *Progress) NewChild(task string, weight float64, opts ...Option) (*Progress, error) {
+	if weight <= 0 {
+		return nil, ErrInvalidWeight
+	}
+
+	c := &child{task: task, weight: weight}
+	sink := &childSink{parent: p, child: c}
+	opts = append([]Option{WithTask(task)}, opts...)
+	childBar, err := NewWithSink(sink, append(opts, WithMinInterval(0))...)
+	if err != nil {
+		return nil, err
+	}
+	unregister(childBar) // The parent is the one that's running
+
+	p.mu.Lock()
+	p.children = append(p.children, c)
+	p.mu.Unlock()
+
+	return childBar, nil
+}
+
+// childPos returns the position of a parent derived from its children. p.mu
+// must be held.
+func (p *Progress) childPos(total int64) int64 {
+	var done, weight float64
+	complete := true
+	for _, c := range p.children {
+		done += c.done * c.weight
+		weight += c.weight
+		complete = complete && c.done == 1
+	}
+
+	// Avoid rounding errors keeping the parent just short of 100%
+	if complete {
+		return total
+	}
+	return int64(done / weight * float64(total))
+}
+
+// childSink passes the progress of a child to its parent.
+type childSink struct {
+	parent *Progress
+	child  *child
+}
+
+func (s *childSink) Post(ctx context.Context, msg *Message) (string, error) {
+	return "child", s.send(ctx, msg)
+}
+
+func (s *childSink) Update(ctx context.Context, id string, msg *Message) error {
+	return s.send(ctx, msg)
+}
+
+func (s *childSink) send(ctx context.Context, msg *Message) error {
+	p := s.parent
+	if msg.Failed {
+		p.mu.Lock()
+		p.phase = s.child.task
+		p.mu.Unlock()
+		return p.FailContext(ctx, msg.Err)
+	}
+
+	p.mu.Lock()
+	if msg.Total > 0 {
+		s.child.done = float64(msg.Pos) / float64(msg.Total)
+	}
+	if msg.Complete {
+		s.child.done = 1
+	}
+
+	phase := s.child.task
+	if msg.Complete {
+		phase = ""
+	}
+	if phase != p.phase {
+		p.phase = phase
+		p.resend = true
+	}
+	pos := p.childPos(p.total())
+	p.mu.Unlock()
+
+	return p.Update64Context(ctx, pos)
+}
+
+// Stage is a named phase of a task that counts for Weight of its progress.
+type Stage struct {
+	Name   string
+	Weight float64
+}
+
+// Stages creates a child progress bar for every stage, in order. Advance
+// through the stages by updating and finishing their progress bars: the
+// parent shows the name of the running stage and the weighted progress of all
+// of them.
+func (p *Progress) Stages(stages []Stage, opts ...Option) ([]*Progress, error) {
+	for _, stage := range stages {
+		if stage.Weight <= 0 {
+			return nil, ErrInvalidWeight
+		}
+	}
+
+	bars := make([]*Progress, len(stages))
+	for i, stage := range stages {
+		var err error
+		if bars[i], err = p.NewChild(stage.Name, stage.Weight, opts...); err != nil {
+			return nil, err
+		}
+	}
+	return bars, nil
+}
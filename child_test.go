@@ -0,0 +1,99 @@
+package progress_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/sfreiberg/progress"
+)
+
+func TestNewChild(t *testing.T) {
+	sink := &memSink{}
+	parent := newProgress(t, sink, progress.WithTask("pipeline"))
+
+	download, err := parent.NewChild("download", 1)
+	if err != nil {
+		t.Fatalf("Error creating child: %s", err)
+	}
+	transform, err := parent.NewChild("transform", 3)
+	if err != nil {
+		t.Fatalf("Error creating child: %s", err)
+	}
+
+	if err := download.Update(50); err != nil {
+		t.Fatalf("Error updating child: %s", err)
+	}
+	if len(sink.posts) != 1 {
+		t.Fatalf("Expected the parent to be posted, got %d posts", len(sink.posts))
+	}
+	if msg := sink.posts[0]; msg.Pct != 12 || msg.Phase != "download" {
+		t.Errorf("Expected 12%% while downloading, got %d%% in %q", msg.Pct, msg.Phase)
+	}
+	if !strings.Contains(sink.posts[0].Text, "pipeline (download)") {
+		t.Errorf("Expected the phase in the message, got %q", sink.posts[0].Text)
+	}
+
+	if err := download.Finish(); err != nil {
+		t.Fatalf("Error finishing child: %s", err)
+	}
+	if err := transform.Update(50); err != nil {
+		t.Fatalf("Error updating child: %s", err)
+	}
+	if msg := sink.updates[len(sink.updates)-1]; msg.Pct != 62 || msg.Phase != "transform" {
+		t.Errorf("Expected 62%% while transforming, got %d%% in %q", msg.Pct, msg.Phase)
+	}
+
+	if err := transform.Finish(); err != nil {
+		t.Fatalf("Error finishing child: %s", err)
+	}
+	if msg := sink.updates[len(sink.updates)-1]; !msg.Complete {
+		t.Errorf("Expected the parent to complete with its children, got %d%%", msg.Pct)
+	}
+}
+
+func TestNewChildFail(t *testing.T) {
+	sink := &memSink{}
+	parent := newProgress(t, sink)
+
+	upload, err := parent.NewChild("upload", 1)
+	if err != nil {
+		t.Fatalf("Error creating child: %s", err)
+	}
+	if err := upload.Fail(errors.New("timeout")); err != nil {
+		t.Fatalf("Error failing child: %s", err)
+	}
+	if len(sink.posts) != 1 || !sink.posts[0].Failed || sink.posts[0].Phase != "upload" {
+		t.Errorf("Expected the parent to fail in the upload phase")
+	}
+
+	if _, err := parent.NewChild("cleanup", 0); err != progress.ErrInvalidWeight {
+		t.Errorf("Expected ErrInvalidWeight, got %v", err)
+	}
+}
+
+func TestStages(t *testing.T) {
+	sink := &memSink{}
+	pbar := newProgress(t, sink)
+
+	stages, err := pbar.Stages([]progress.Stage{{"download", 30}, {"transform", 50}, {"upload", 20}})
+	if err != nil {
+		t.Fatalf("Error creating stages: %s", err)
+	}
+
+	for i, want := range []int{30, 80, 100} {
+		if err := stages[i].Update(50); err != nil {
+			t.Fatalf("Error updating stage: %s", err)
+		}
+		if err := stages[i].Finish(); err != nil {
+			t.Fatalf("Error finishing stage: %s", err)
+		}
+		if msg := sink.updates[len(sink.updates)-1]; msg.Pct != want {
+			t.Errorf("Expected %d%% after stage %d, got %d%%", want, i, msg.Pct)
+		}
+	}
+
+	if _, err := pbar.Stages([]progress.Stage{{"verify", -1}}); err != progress.ErrInvalidWeight {
+		t.Errorf("Expected ErrInvalidWeight, got %v", err)
+	}
+}
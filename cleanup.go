@@ -0,0 +1,71 @@
+package progress
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+)
+
+// Cleanup is what happens to the progress message once the task completes.
+type Cleanup int
+
+const (
+	CleanupNone    Cleanup = iota // Leave the progress bar as it is
+	CleanupDelete                 // Delete the message. The sink must be a Deleter.
+	CleanupSummary                // Replace the message with a one line summary
+)
+
+// ErrCantDelete is returned by NewWithSink when Options.Cleanup is
+// CleanupDelete but the sink can't delete messages.
+var ErrCantDelete = errors.New("Sink can't delete messages")
+
+// Deleter is implemented by sinks that can delete a message they posted.
+type Deleter interface {
+	Delete(ctx context.Context, id string) error
+}
+
+// cleanup deletes or summarizes the completed message id, after
+// Options.CleanupAfter if it's set. summary is the rendered summary used by
+// CleanupSummary. p.sendMu must be held but not p.mu.
+func (p *Progress) cleanup(ctx context.Context, id string, msg *Message, summary string) {
+	if p.Opts.CleanupAfter <= 0 {
+		p.cleanupNow(ctx, id, msg, summary)
+		return
+	}
+
+	go func() {
+		timer := time.NewTimer(p.Opts.CleanupAfter)
+		defer timer.Stop()
+
+		select {
+		case <-timer.C:
+		case <-p.closed:
+			return
+		}
+
+		p.sendMu.Lock()
+		defer p.sendMu.Unlock()
+		p.cleanupNow(context.Background(), id, msg, summary)
+	}()
+}
+
+func (p *Progress) cleanupNow(ctx context.Context, id string, msg *Message, summary string) {
+	var err error
+	switch p.Opts.Cleanup {
+	case CleanupDelete:
+		err = p.sink.(Deleter).Delete(ctx, id)
+	case CleanupSummary:
+		m := *msg
+		m.Text = summary
+		m.Summary = true
+		err = p.sink.Update(ctx, id, &m)
+	}
+
+	if err != nil {
+		p.log(ctx, slog.LevelError, "Error cleaning up message", "error", err)
+		if p.Opts.OnError != nil {
+			p.Opts.OnError(err)
+		}
+	}
+}
@@ -0,0 +1,51 @@
+package progress_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sfreiberg/progress"
+	"github.com/sfreiberg/progress/progresstest"
+)
+
+func TestCleanupDelete(t *testing.T) {
+	pbar, sink := progresstest.New(t, progress.WithCleanup(progress.CleanupDelete, 20*time.Millisecond))
+	defer pbar.Close()
+
+	if err := pbar.Finish(); err != nil {
+		t.Fatalf("Error finishing progress bar: %s", err)
+	}
+	if len(sink.Deleted()) != 0 {
+		t.Fatalf("Expected the message to be kept until CleanupAfter, got %v", sink.Deleted())
+	}
+
+	deadline := time.Now().Add(time.Second)
+	for len(sink.Deleted()) == 0 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	if deleted := sink.Deleted(); len(deleted) != 1 || deleted[0] != "1" {
+		t.Errorf("Expected message 1 to be deleted, got %v", deleted)
+	}
+}
+
+func TestCleanupSummary(t *testing.T) {
+	pbar, sink := progresstest.New(t, progress.WithTask("import"), progress.WithCleanup(progress.CleanupSummary, 0))
+
+	if err := pbar.Finish(); err != nil {
+		t.Fatalf("Error finishing progress bar: %s", err)
+	}
+
+	last := sink.Last()
+	if !last.Summary || !strings.HasPrefix(last.Text, "✅ *import* completed in") {
+		t.Errorf("Expected the message to be replaced with a summary, got %+v", last)
+	}
+}
+
+func TestCleanupCantDelete(t *testing.T) {
+	_, err := progress.NewWithSink(&memSink{}, progress.WithCleanup(progress.CleanupDelete, 0))
+	if !errors.Is(err, progress.ErrCantDelete) {
+		t.Errorf("Expected ErrCantDelete, got %v", err)
+	}
+}
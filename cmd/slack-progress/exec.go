@@ -0,0 +1,140 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os/exec"
+	"sync"
+
+	"github.com/sfreiberg/progress"
+)
+
+// runCommand runs the command args with its output passed through to stdout
+// and stderr unchanged and updates pbar with the progress parse finds in
+// either. The task completes when the command succeeds and fails with its
+// error otherwise.
+func runCommand(pbar *progress.Progress, parse parser, args []string, stdin io.Reader, stdout, stderr io.Writer) error {
+	w := &outputWatcher{pbar: pbar, parse: parse, stderr: stderr, total: pbar.Snapshot().Total}
+
+	cmd := exec.Command(args[0], args[1:]...)
+	cmd.Stdin = stdin
+	cmd.Stdout = io.MultiWriter(stdout, w.watch())
+	cmd.Stderr = io.MultiWriter(stderr, w.watch())
+	err := cmd.Run()
+	w.close()
+
+	if doneErr := pbar.Done(err); err == nil {
+		err = doneErr
+	}
+	return err
+}
+
+// outputWatcher parses the output of a command line by line.
+type outputWatcher struct {
+	pbar   *progress.Progress
+	parse  parser
+	stderr io.Writer
+
+	mu     sync.Mutex // Serializes parse, which may keep state
+	total  int64
+	wg     sync.WaitGroup
+	pipes  []*io.PipeWriter
+	warned bool
+}
+
+// watch returns a writer whose output is parsed until close is called.
+func (w *outputWatcher) watch() io.Writer {
+	r, pw := io.Pipe()
+	w.pipes = append(w.pipes, pw)
+	w.wg.Add(1)
+	go func() {
+		defer w.wg.Done()
+		scanner := bufio.NewScanner(r)
+		scanner.Split(scanLines)
+		for scanner.Scan() {
+			w.line(scanner.Text())
+		}
+		// Keep draining so the command never blocks on its output.
+		io.Copy(io.Discard, r)
+	}()
+	return pw
+}
+
+// line updates the progress bar with the progress in line, if any. The
+// position stays below the total until the command exits so the task doesn't
+// complete early.
+func (w *outputWatcher) line(line string) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	pos, total, ok := w.parse(line)
+	if !ok {
+		return
+	}
+	if total > 0 && total != w.total {
+		if err := w.pbar.SetTotal64(total); err != nil {
+			w.warn(err)
+			return
+		}
+		w.total = total
+	}
+	if pos >= w.total {
+		pos = w.total - 1
+	}
+	if err := w.pbar.Update64(pos); err != nil {
+		w.warn(err)
+	}
+}
+
+// warn reports the first error updating the progress bar.
+func (w *outputWatcher) warn(err error) {
+	if !w.warned {
+		w.warned = true
+		fmt.Fprintln(w.stderr, "slack-progress:", err)
+	}
+}
+
+// close waits for all output to be parsed.
+func (w *outputWatcher) close() {
+	for _, pw := range w.pipes {
+		pw.Close()
+	}
+	w.wg.Wait()
+}
+
+// scanLines is bufio.ScanLines that also ends lines at carriage returns,
+// which tools like rsync and rclone use to redraw their progress.
+func scanLines(data []byte, atEOF bool) (int, []byte, error) {
+	if i := bytes.IndexAny(data, "\r\n"); i >= 0 {
+		return i + 1, data[:i], nil
+	}
+	if atEOF && len(data) > 0 {
+		return len(data), data, nil
+	}
+	return 0, nil, nil
+}
+
+// lockedWriter serializes the writes to w.
+type lockedWriter struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+func (lw *lockedWriter) Write(b []byte) (int, error) {
+	lw.mu.Lock()
+	defer lw.mu.Unlock()
+	return lw.w.Write(b)
+}
+
+// exitCode returns the exit code of the command that failed with err and
+// whether err is an exit error at all.
+func exitCode(err error) (int, bool) {
+	var exitErr *exec.ExitError
+	if !errors.As(err, &exitErr) {
+		return 0, false
+	}
+	return exitErr.ExitCode(), true
+}
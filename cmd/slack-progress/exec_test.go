@@ -0,0 +1,66 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRunCommand(t *testing.T) {
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("sh not found")
+	}
+	t.Setenv("SLACK_TOKEN", "")
+	var stdout, stderr bytes.Buffer
+	args := []string{"--task", "Copy", "--regex", `(?P<pos>\d+)/(?P<total>\d+)`, "--", "sh", "-c", `printf 'copied 10/40\rcopied 40/40\n'; echo oops >&2`}
+
+	if err := run(args, strings.NewReader(""), &stdout, &stderr); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := stdout.String(); got != "copied 10/40\rcopied 40/40\n" {
+		t.Errorf("Expected the output of the command unchanged, got %q", got)
+	}
+	if out := stderr.String(); !strings.Contains(out, "oops") || !strings.Contains(out, "100%") {
+		t.Errorf("Expected the errors of the command and the finished bar, got %q", out)
+	}
+}
+
+func TestRunCommandFails(t *testing.T) {
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("sh not found")
+	}
+	t.Setenv("SLACK_TOKEN", "")
+	var stdout, stderr bytes.Buffer
+
+	err := run([]string{"--", "sh", "-c", "echo 50%; exit 3"}, strings.NewReader(""), &stdout, &stderr)
+	if code, ok := exitCode(err); !ok || code != 3 {
+		t.Errorf("Expected exit code 3, got %v", err)
+	}
+	if !strings.Contains(stderr.String(), "exit status 3") {
+		t.Errorf("Expected the failure on stderr, got %q", stderr.String())
+	}
+}
+
+func TestRunCommandWithConfig(t *testing.T) {
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("sh not found")
+	}
+	t.Setenv("SLACK_TOKEN", "")
+	config := filepath.Join(t.TempDir(), "progress.yaml")
+	if err := os.WriteFile(config, []byte("width: 20\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	var stdout, stderr bytes.Buffer
+
+	if err := run([]string{"--config", config, "--", "sh", "-c", "echo 50%"}, strings.NewReader(""), &stdout, &stderr); err != nil {
+		t.Fatal(err)
+	}
+
+	if out := stderr.String(); !strings.Contains(out, "sh -c echo 50%") {
+		t.Errorf("Expected the command as the task with --config, got %q", out)
+	}
+}
@@ -0,0 +1,193 @@
+// Command slack-progress shows the progress of shell scripts and Makefiles
+// in slack. It reads positions from stdin, one per line, either as a number
+// or as pos/total when the total changes:
+//
+//	mytool | slack-progress --channel deploys --total 500
+//
+// The bot token is read from --token or SLACK_TOKEN and the channel from
+// --channel or SLACK_CHANNEL. Without a token the bar is drawn on stderr. The
+// task is complete once stdin is closed.
+//
+// With --pipe it copies stdin to stdout unchanged instead, like pv, and
+// reports the bytes copied, the throughput and the time remaining:
+//
+//	cat dump.sql | slack-progress --pipe --size 2G | psql
+//
+// --size may be left out when stdin is a file.
+//
+// Given a command after the flags it runs the command and extracts the
+// progress from its output, passing the output through unchanged:
+//
+//	slack-progress --channel backups --parser rsync -- rsync -a --info=progress2 src/ dst/
+//
+// --regex sets a pattern with named groups pos and total, or pct for a
+// percentage, and --parser picks a built-in one for rsync, rclone or docker
+// pull. Without either percentages like "42%" are used. slack-progress exits
+// with the exit code of the command.
+package main
+
+import (
+	"bufio"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/sfreiberg/progress"
+)
+
+func main() {
+	if err := run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr); err != nil {
+		if code, ok := exitCode(err); ok {
+			os.Exit(code)
+		}
+		if !errors.Is(err, flag.ErrHelp) {
+			fmt.Fprintln(os.Stderr, "slack-progress:", err)
+		}
+		os.Exit(1)
+	}
+}
+
+// run runs slack-progress with the command line arguments args.
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) error {
+	stderr = &lockedWriter{w: stderr} // Shared by the terminal and commands
+	flags := flag.NewFlagSet("slack-progress", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	token := flags.String("token", os.Getenv(progress.EnvToken), "slack bot token, defaults to $"+progress.EnvToken)
+	channel := flags.String("channel", os.Getenv(progress.EnvChannel), "channel ID or name, defaults to $"+progress.EnvChannel)
+	task := flags.String("task", "", "name of the task")
+	total := flags.Int64("total", 100, "total number of units")
+	pipe := flags.Bool("pipe", false, "copy stdin to stdout and count the bytes")
+	size := flags.String("size", "", "number of bytes expected with --pipe, e.g. 2G")
+	pattern := flags.String("regex", "", "regex with pos and total or pct groups for the command's output")
+	parserName := flags.String("parser", "", "built-in parser for the command's output: rsync, rclone or docker")
+	config := flags.String("config", "", "JSON or YAML file with options, see progress.LoadOptions")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if *token != "" && *channel == "" {
+		return errors.New("--channel or $" + progress.EnvChannel + " is required with a token")
+	}
+
+	command := flags.Args()
+	var parse parser
+	if len(command) > 0 {
+		if *pipe {
+			return errors.New("--pipe can't be used with a command")
+		}
+		var err error
+		if parse, err = newParser(*parserName, *pattern); err != nil {
+			return err
+		}
+	}
+
+	var opts []progress.Option
+	// The options in the file replace everything before them so they go
+	// first
+	if *config != "" {
+		o, err := loadOptions(*config)
+		if err != nil {
+			return err
+		}
+		opts = append(opts, o)
+	}
+	if len(command) > 0 {
+		opts = append(opts, progress.WithTask(strings.Join(command, " ")))
+	}
+	if *pipe {
+		n, err := pipeSize(*size, stdin)
+		if err != nil {
+			return err
+		}
+		*total = n
+		opts = append(opts, progress.WithTemplate(pipeMsg))
+	}
+	if *task != "" {
+		opts = append(opts, progress.WithTask(*task))
+	}
+	opts = append(opts, progress.WithTotal64(*total))
+	if *token == "" {
+		opts = append(opts, progress.WithTerminal(stderr))
+	}
+
+	pbar, err := progress.New(*token, channelName(*channel), opts...)
+	if err != nil {
+		return err
+	}
+	defer pbar.Close()
+
+	switch {
+	case len(command) > 0:
+		return runCommand(pbar, parse, command, stdin, stdout, stderr)
+	case *pipe:
+		return copyPipe(pbar, stdin, stdout)
+	}
+	return readPositions(pbar, stdin, stderr)
+}
+
+// loadOptions reads the options in the file name.
+func loadOptions(name string) (*progress.Options, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return progress.LoadOptions(f)
+}
+
+// channelID matches slack channel IDs, which are used as they are.
+var channelID = regexp.MustCompile(`^[CGD][A-Z0-9]{8,}$`)
+
+// channelName returns the channel for progress.New, adding the # of channel
+// names that are given without it.
+func channelName(channel string) string {
+	if channel == "" || strings.HasPrefix(channel, "#") || channelID.MatchString(channel) {
+		return channel
+	}
+	return "#" + channel
+}
+
+// readPositions updates pbar with the positions read from r until it's
+// closed and then finishes the task. Lines that aren't positions are
+// reported to stderr and skipped.
+func readPositions(pbar *progress.Progress, r io.Reader, stderr io.Writer) error {
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		if err := updateLine(pbar, line); err != nil {
+			fmt.Fprintf(stderr, "slack-progress: %q: %s\n", line, err)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+
+	return pbar.Finish()
+}
+
+// updateLine updates pbar with a line that's either a position or
+// pos/total.
+func updateLine(pbar *progress.Progress, line string) error {
+	posText, totalText, hasTotal := strings.Cut(line, "/")
+	pos, err := strconv.ParseInt(strings.TrimSpace(posText), 10, 64)
+	if err != nil {
+		return fmt.Errorf("Invalid progress line, expected pos or pos/total: %w", err)
+	}
+	if hasTotal {
+		total, err := strconv.ParseInt(strings.TrimSpace(totalText), 10, 64)
+		if err != nil {
+			return fmt.Errorf("Invalid progress line, expected pos or pos/total: %w", err)
+		}
+		if err := pbar.SetTotal64(total); err != nil {
+			return err
+		}
+	}
+	return pbar.Update64(pos)
+}
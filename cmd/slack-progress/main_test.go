@@ -0,0 +1,49 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestRun(t *testing.T) {
+	t.Setenv("SLACK_TOKEN", "")
+	var stdout, stderr bytes.Buffer
+	stdin := strings.NewReader("1\n 5 \n\nbogus\n6/many\n7/20\n")
+
+	if err := run([]string{"--task", "Copy", "--total", "10"}, stdin, &stdout, &stderr); err != nil {
+		t.Fatal(err)
+	}
+
+	out := stderr.String()
+	if !strings.Contains(out, `"bogus": Invalid progress line`) || !strings.Contains(out, `"6/many": Invalid progress line`) {
+		t.Errorf("Expected a warning about the bogus lines, got %q", out)
+	}
+	if !strings.Contains(out, "Copy") || !strings.Contains(out, "100%") {
+		t.Errorf("Expected the finished bar, got %q", out)
+	}
+	if stdout.Len() != 0 {
+		t.Errorf("Expected nothing on stdout, got %q", stdout.String())
+	}
+}
+
+func TestRunRequiresChannel(t *testing.T) {
+	t.Setenv("SLACK_CHANNEL", "")
+	var stdout, stderr bytes.Buffer
+	if err := run([]string{"--token", "xoxb-test"}, strings.NewReader(""), &stdout, &stderr); err == nil {
+		t.Error("Expected an error without a channel")
+	}
+}
+
+func TestChannelName(t *testing.T) {
+	for in, want := range map[string]string{
+		"":            "",
+		"deploys":     "#deploys",
+		"#deploys":    "#deploys",
+		"C0123456789": "C0123456789",
+	} {
+		if got := channelName(in); got != want {
+			t.Errorf("channelName(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
@@ -0,0 +1,110 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// parser extracts the position and total from a line of output. ok is false
+// if the line has no progress. total is 0 if it's unchanged.
+type parser func(line string) (pos, total int64, ok bool)
+
+// parsers are the built-in parsers for --parser.
+var parsers = map[string]func() parser{
+	// rsync --info=progress2, e.g. "  1,234,567  45%  1.23MB/s  0:01:23".
+	"rsync": func() parser { return regexParser(regexp.MustCompile(`(?P<pct>\d+)%\s+\S+/s`)) },
+	// rclone --progress or --stats, e.g. "Transferred: 1.2 GiB / 2 GiB, 62%,
+	// 10 MiB/s, ETA 1m18s".
+	"rclone": func() parser {
+		return regexParser(regexp.MustCompile(`Transferred:\s+[\d.]+\s*\w*B\s*/\s*[\d.]+\s*\w*B,\s*(?P<pct>\d+)%`))
+	},
+	"docker": dockerParser,
+}
+
+// defaultPattern is the regex used when neither --regex nor --parser is
+// given. It matches percentages like "42%" or "42.5%".
+const defaultPattern = `(?P<pct>\d+(?:\.\d+)?)%`
+
+// newParser creates the parser for --parser name or --regex pattern.
+func newParser(name, pattern string) (parser, error) {
+	switch {
+	case name != "" && pattern != "":
+		return nil, errors.New("--parser and --regex can't be used together")
+	case name != "":
+		newParser, ok := parsers[name]
+		if !ok {
+			return nil, fmt.Errorf("Unknown parser %q, use rsync, rclone or docker", name)
+		}
+		return newParser(), nil
+	case pattern == "":
+		pattern = defaultPattern
+	}
+
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("Invalid regex: %w", err)
+	}
+	if re.SubexpIndex("pos") < 0 && re.SubexpIndex("pct") < 0 {
+		return nil, errors.New("The regex needs a (?P<pos>...) or (?P<pct>...) group")
+	}
+	return regexParser(re), nil
+}
+
+// regexParser extracts the progress from the named groups of re: pos and
+// total, e.g. `(?P<pos>\d+)/(?P<total>\d+)`, or pct for a percentage.
+// Thousands separators are ignored.
+func regexParser(re *regexp.Regexp) parser {
+	group := func(m []string, name string) (float64, bool) {
+		i := re.SubexpIndex(name)
+		if i < 0 || m[i] == "" {
+			return 0, false
+		}
+		n, err := strconv.ParseFloat(strings.ReplaceAll(m[i], ",", ""), 64)
+		return n, err == nil
+	}
+
+	return func(line string) (int64, int64, bool) {
+		m := re.FindStringSubmatch(line)
+		if m == nil {
+			return 0, 0, false
+		}
+		if pct, ok := group(m, "pct"); ok {
+			return int64(pct), 100, true
+		}
+		pos, ok := group(m, "pos")
+		if !ok {
+			return 0, 0, false
+		}
+		total, _ := group(m, "total")
+		return int64(pos), int64(total), true
+	}
+}
+
+// dockerLayer matches the status lines docker pull prints for every layer,
+// e.g. "a1b2c3d4e5f6: Pull complete".
+var dockerLayer = regexp.MustCompile(`^([0-9a-f]{12}): (.+)$`)
+
+// dockerParser counts the layers docker pull has finished out of the layers
+// it has listed.
+func dockerParser() parser {
+	layers := map[string]bool{} // Whether each layer is done
+	done := 0
+	return func(line string) (int64, int64, bool) {
+		m := dockerLayer.FindStringSubmatch(strings.TrimSpace(line))
+		if m == nil {
+			return 0, 0, false
+		}
+
+		finished := m[2] == "Pull complete" || m[2] == "Already exists"
+		if wasDone, ok := layers[m[1]]; !ok || finished && !wasDone {
+			layers[m[1]] = finished
+			if finished {
+				done++
+			}
+		}
+		return int64(done), int64(len(layers)), true
+	}
+}
@@ -0,0 +1,69 @@
+package main
+
+import "testing"
+
+func TestParsers(t *testing.T) {
+	tests := []struct {
+		parser, line string
+		pos, total   int64
+		ok           bool
+	}{
+		{"rsync", "    123,456,789  45%   12.34MB/s    0:01:23 (xfr#3, to-chk=10/20)", 45, 100, true},
+		{"rsync", "sending incremental file list", 0, 0, false},
+		{"rclone", "Transferred:   	    1.234 GiB / 2.000 GiB, 62%, 10.000 MiB/s, ETA 1m18s", 62, 100, true},
+		{"rclone", "Transferred:            3 / 10, 30%", 0, 0, false},
+	}
+	for _, test := range tests {
+		parse, err := newParser(test.parser, "")
+		if err != nil {
+			t.Fatal(err)
+		}
+		pos, total, ok := parse(test.line)
+		if pos != test.pos || total != test.total || ok != test.ok {
+			t.Errorf("%s(%q) = %d, %d, %v; want %d, %d, %v", test.parser, test.line, pos, total, ok, test.pos, test.total, test.ok)
+		}
+	}
+}
+
+func TestDockerParser(t *testing.T) {
+	parse := dockerParser()
+	lines := []struct {
+		line       string
+		pos, total int64
+	}{
+		{"a1b2c3d4e5f6: Pulling fs layer", 0, 1},
+		{"b2c3d4e5f6a1: Already exists", 1, 2},
+		{"a1b2c3d4e5f6: Download complete", 1, 2},
+		{"a1b2c3d4e5f6: Pull complete", 2, 2},
+		{"a1b2c3d4e5f6: Pull complete", 2, 2},
+	}
+	for _, l := range lines {
+		if pos, total, ok := parse(l.line); !ok || pos != l.pos || total != l.total {
+			t.Errorf("parse(%q) = %d, %d, %v; want %d, %d", l.line, pos, total, ok, l.pos, l.total)
+		}
+	}
+	if _, _, ok := parse("Digest: sha256:0123"); ok {
+		t.Error("Expected no progress in the digest")
+	}
+}
+
+func TestNewParser(t *testing.T) {
+	for _, test := range []struct{ name, pattern string }{
+		{"rsync", `(?P<pct>\d+)%`},
+		{"curl", ""},
+		{"", `(\d+)%`},
+		{"", `(?P<pct>\d+%`},
+	} {
+		if _, err := newParser(test.name, test.pattern); err == nil {
+			t.Errorf("Expected an error for --parser %q --regex %q", test.name, test.pattern)
+		}
+	}
+
+	parse, err := newParser("", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pos, total, ok := parse("Downloading 42.5% done"); !ok || pos != 42 || total != 100 {
+		t.Errorf("Expected 42 of 100, got %d, %d, %v", pos, total, ok)
+	}
+}
@@ -0,0 +1,65 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/sfreiberg/progress"
+)
+
+// pipeMsg is the message shown in pipe mode, with the bytes copied so far
+// and the throughput.
+const pipeMsg = "{{.Task}}{{ if .Phase }} ({{ .Phase }}){{ end }}\n`{{.ProgBar}}` {{.Pos}}%" +
+	" ({{ humanBytes .Current }} / {{ humanBytes .Total }}, {{ humanBytes .Rate }}/s)\n" +
+	"{{ if .Complete }}Completed in *{{ .Elapsed }}*" +
+	"{{ else }}{{ .Remaining }} remaining...{{ end }}" +
+	"{{ if .Stalled }}\n⚠️ stalled for {{ .Stalled }}{{ end }}"
+
+// pipeSize returns the number of bytes expected on stdin in pipe mode. It's
+// size if given and otherwise the size of stdin if it's a regular file.
+func pipeSize(size string, stdin io.Reader) (int64, error) {
+	if size != "" {
+		return parseSize(size)
+	}
+	if f, ok := stdin.(*os.File); ok {
+		if info, err := f.Stat(); err == nil && info.Mode().IsRegular() && info.Size() > 0 {
+			return info.Size(), nil
+		}
+	}
+	return 0, errors.New("--size is required with --pipe when stdin isn't a file")
+}
+
+// parseSize parses a number of bytes with an optional binary unit, e.g.
+// "512", "64K", "1.5M" or "2GiB".
+func parseSize(s string) (int64, error) {
+	text := strings.TrimSuffix(strings.TrimSuffix(strings.ToUpper(strings.TrimSpace(s)), "B"), "I")
+	mult := 1.0
+	if text != "" {
+		if i := strings.IndexByte("KMGTP", text[len(text)-1]); i >= 0 {
+			mult = math.Pow(1024, float64(i+1))
+			text = text[:len(text)-1]
+		}
+	}
+
+	n, err := strconv.ParseFloat(text, 64)
+	if err != nil || n <= 0 {
+		return 0, fmt.Errorf("Invalid size %q", s)
+	}
+	return int64(n * mult), nil
+}
+
+// copyPipe copies stdin to stdout unchanged, advancing pbar by every byte,
+// and completes or fails the task depending on the outcome. Errors copying
+// take precedence over errors updating the progress bar.
+func copyPipe(pbar *progress.Progress, stdin io.Reader, stdout io.Writer) error {
+	_, err := io.Copy(stdout, pbar.NewProxyReader(stdin))
+	if doneErr := pbar.Done(err); err == nil {
+		err = doneErr
+	}
+	return err
+}
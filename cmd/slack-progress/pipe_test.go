@@ -0,0 +1,71 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRunPipe(t *testing.T) {
+	t.Setenv("SLACK_TOKEN", "")
+	data := strings.Repeat("INSERT INTO t VALUES (1);\n", 1000)
+	var stdout, stderr bytes.Buffer
+
+	if err := run([]string{"--pipe", "--size", "26000"}, strings.NewReader(data), &stdout, &stderr); err != nil {
+		t.Fatal(err)
+	}
+
+	if stdout.String() != data {
+		t.Error("Expected stdin to be copied to stdout unchanged")
+	}
+	if out := stderr.String(); !strings.Contains(out, "100%") || !strings.Contains(out, "25.4 KiB / 25.4 KiB") {
+		t.Errorf("Expected the bytes copied on stderr, got %q", out)
+	}
+}
+
+func TestRunPipeRequiresSize(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	if err := run([]string{"--pipe"}, strings.NewReader("data"), &stdout, &stderr); err == nil {
+		t.Error("Expected an error without a size")
+	}
+}
+
+func TestParseSize(t *testing.T) {
+	for in, want := range map[string]int64{
+		"512":  512,
+		"64K":  64 << 10,
+		"1.5M": 3 << 19,
+		"2G":   2 << 30,
+		"2GiB": 2 << 30,
+		"1tb":  1 << 40,
+	} {
+		if got, err := parseSize(in); err != nil || got != want {
+			t.Errorf("parseSize(%q) = %d, %v; want %d", in, got, err, want)
+		}
+	}
+
+	for _, in := range []string{"", "G", "-1", "2X"} {
+		if _, err := parseSize(in); err == nil {
+			t.Errorf("Expected an error parsing %q", in)
+		}
+	}
+}
+
+func TestRunPipeWithConfig(t *testing.T) {
+	t.Setenv("SLACK_TOKEN", "")
+	config := filepath.Join(t.TempDir(), "progress.yaml")
+	if err := os.WriteFile(config, []byte("width: 20\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	var stdout, stderr bytes.Buffer
+
+	if err := run([]string{"--pipe", "--size", "4", "--config", config}, strings.NewReader("data"), &stdout, &stderr); err != nil {
+		t.Fatal(err)
+	}
+
+	if out := stderr.String(); !strings.Contains(out, "4 B / 4 B") {
+		t.Errorf("Expected the pipe template with --config, got %q", out)
+	}
+}
@@ -0,0 +1,115 @@
+package progress
+
+import (
+	"fmt"
+	"io"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// optionsFile is the part of Options that can be loaded with LoadOptions.
+// Fields that aren't set in the file are left alone.
+type optionsFile struct {
+	Task             *string        `yaml:"task"`
+	Msg              *string        `yaml:"msg"`
+	FailMsg          *string        `yaml:"fail_msg"`
+	CompleteMsg      *string        `yaml:"complete_msg"`
+	CompleteReply    *bool          `yaml:"complete_reply"`
+	Theme            *string        `yaml:"theme"`
+	Fill             *string        `yaml:"fill"`
+	Empty            *string        `yaml:"empty"`
+	FailFill         *string        `yaml:"fail_fill"`
+	OverdueFill      *string        `yaml:"overdue_fill"`
+	Fills            *[]string      `yaml:"fills"`
+	BarLeft          *string        `yaml:"bar_left"`
+	BarRight         *string        `yaml:"bar_right"`
+	SubBlocks        *bool          `yaml:"sub_blocks"`
+	Width            *int           `yaml:"width"`
+	TotalUnits       *int           `yaml:"total_units"`
+	Unit             *string        `yaml:"unit"`
+	Precision        *int           `yaml:"precision"`
+	ShowEstTime      *bool          `yaml:"show_est_time"`
+	ETALayout        *string        `yaml:"eta_layout"`
+	Location         *string        `yaml:"location"`
+	LogLines         *int           `yaml:"log_lines"`
+	MaxLength        *int           `yaml:"max_length"`
+	MinInterval      *time.Duration `yaml:"min_interval"`
+	Refresh          *time.Duration `yaml:"refresh"`
+	StallAfter       *time.Duration `yaml:"stall_after"`
+	Milestones       *[]int         `yaml:"milestones"`
+	Mentions         *[]string      `yaml:"mentions"`
+	CompleteMentions *[]string      `yaml:"complete_mentions"`
+	History          *int           `yaml:"history"`
+	AllowDecrease    *bool          `yaml:"allow_decrease"`
+	AsUser           *bool          `yaml:"as_user"`
+	Blocks           *bool          `yaml:"blocks"`
+	Reactions        *bool          `yaml:"reactions"`
+	Metadata         *bool          `yaml:"metadata"`
+}
+
+// LoadOptions reads Options from a JSON or YAML document, so the look of the
+// progress messages can be changed without recompiling. Keys are the snake
+// case names of the fields, e.g. fill, fail_msg or min_interval, and
+// durations are strings like "2s". Fields that aren't in the document keep
+// their DefaultOptions value. Functions, clients and other fields that can't
+// be written down have to be set in code.
+func LoadOptions(r io.Reader) (*Options, error) {
+	var f optionsFile
+	if err := yaml.NewDecoder(r).Decode(&f); err != nil && err != io.EOF {
+		return nil, fmt.Errorf("Invalid options: %w", err)
+	}
+
+	o := DefaultOptions("Unknown Task")
+	set(&o.Task, f.Task)
+	set(&o.Msg, f.Msg)
+	set(&o.FailMsg, f.FailMsg)
+	set(&o.CompleteMsg, f.CompleteMsg)
+	set(&o.CompleteReply, f.CompleteReply)
+	set(&o.Theme, f.Theme)
+	set(&o.Fill, f.Fill)
+	set(&o.Empty, f.Empty)
+	set(&o.FailFill, f.FailFill)
+	set(&o.OverdueFill, f.OverdueFill)
+	set(&o.Fills, f.Fills)
+	set(&o.BarLeft, f.BarLeft)
+	set(&o.BarRight, f.BarRight)
+	set(&o.SubBlocks, f.SubBlocks)
+	set(&o.Width, f.Width)
+	set(&o.TotalUnits, f.TotalUnits)
+	set(&o.Unit, f.Unit)
+	set(&o.Precision, f.Precision)
+	set(&o.ShowEstTime, f.ShowEstTime)
+	set(&o.ETALayout, f.ETALayout)
+	set(&o.LogLines, f.LogLines)
+	set(&o.MaxLength, f.MaxLength)
+	set(&o.MinInterval, f.MinInterval)
+	set(&o.Refresh, f.Refresh)
+	set(&o.StallAfter, f.StallAfter)
+	set(&o.Milestones, f.Milestones)
+	set(&o.Mentions, f.Mentions)
+	set(&o.CompleteMentions, f.CompleteMentions)
+	set(&o.History, f.History)
+	set(&o.AllowDecrease, f.AllowDecrease)
+	set(&o.AsUser, f.AsUser)
+	set(&o.Blocks, f.Blocks)
+	set(&o.Reactions, f.Reactions)
+	set(&o.Metadata, f.Metadata)
+
+	if f.Location != nil {
+		loc, err := time.LoadLocation(*f.Location)
+		if err != nil {
+			return nil, fmt.Errorf("Invalid location %q: %w", *f.Location, err)
+		}
+		o.Location = loc
+	}
+
+	return o, nil
+}
+
+// set sets *dst to *src if src isn't nil.
+func set[T any](dst, src *T) {
+	if src != nil {
+		*dst = *src
+	}
+}
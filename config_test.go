@@ -0,0 +1,33 @@
+package progress_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sfreiberg/progress"
+)
+
+func TestLoadOptions(t *testing.T) {
+	docs := map[string]string{
+		"yaml": "task: deploy\ntheme: moons\nwidth: 20\nmin_interval: 2s\nmentions: [U123]\nmsg: \"{{.Task}} {{.Pos}}%\"\n",
+		"json": `{"task": "deploy", "theme": "moons", "width": 20, "min_interval": "2s", "mentions": ["U123"], "msg": "{{.Task}} {{.Pos}}%"}`,
+	}
+
+	for format, doc := range docs {
+		o, err := progress.LoadOptions(strings.NewReader(doc))
+		if err != nil {
+			t.Fatalf("Error loading %s options: %s", format, err)
+		}
+		if o.Task != "deploy" || o.Theme != "moons" || o.Width != 20 || o.MinInterval != 2*time.Second || len(o.Mentions) != 1 || o.Msg != "{{.Task}} {{.Pos}}%" {
+			t.Errorf("Unexpected %s options: %+v", format, o)
+		}
+		if o.Empty != "⬜" || o.MaxAttempts != 3 {
+			t.Errorf("Expected the defaults to be kept for %s, got %q and %d", format, o.Empty, o.MaxAttempts)
+		}
+	}
+
+	if _, err := progress.LoadOptions(strings.NewReader("width: wide")); err == nil {
+		t.Error("Expected an error for an invalid width")
+	}
+}
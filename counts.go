@@ -0,0 +1,57 @@
+package progress
+
+// Counts are the number of items that succeeded, failed or were skipped.
+type Counts struct {
+	Success int64
+	Failed  int64
+	Skipped int64
+}
+
+// Indexes into Progress.tally
+const (
+	countSuccess = iota
+	countFailed
+	countSkipped
+)
+
+// IncSuccess counts an item that succeeded and advances the progress bar by
+// one.
+func (p *Progress) IncSuccess() error {
+	return p.inc(countSuccess)
+}
+
+// IncFailed counts an item that failed and advances the progress bar by one.
+// Unlike Fail the task keeps going.
+func (p *Progress) IncFailed() error {
+	return p.inc(countFailed)
+}
+
+// IncSkipped counts an item that was skipped and advances the progress bar by
+// one.
+func (p *Progress) IncSkipped() error {
+	return p.inc(countSkipped)
+}
+
+func (p *Progress) inc(i int) error {
+	p.tally[i].Add(1)
+	return p.Add(1)
+}
+
+// counts returns the items counted so far.
+func (p *Progress) counts() Counts {
+	return Counts{
+		Success: p.tally[countSuccess].Load(),
+		Failed:  p.tally[countFailed].Load(),
+		Skipped: p.tally[countSkipped].Load(),
+	}
+}
+
+// countSegments returns the segments drawn for the counts when
+// Options.CountBar is set.
+func (p *Progress) countSegments(c Counts) []Segment {
+	return []Segment{
+		{Fill: p.Opts.SuccessFill, Count: c.Success},
+		{Fill: p.Opts.FailedFill, Count: c.Failed},
+		{Fill: p.Opts.SkippedFill, Count: c.Skipped},
+	}
+}
@@ -0,0 +1,48 @@
+package progress_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/sfreiberg/progress"
+)
+
+func TestCounts(t *testing.T) {
+	sink := &memSink{}
+	pbar := newProgress(t, sink,
+		progress.WithTotal(10),
+		progress.WithTemplate("{{.Counts.Success}} ok, {{.Counts.Failed}} failed, {{.Counts.Skipped}} skipped"),
+		progress.WithCountBar("S", "F", "K"),
+		progress.WithEmpty("."),
+	)
+
+	for _, inc := range []func() error{pbar.IncSuccess, pbar.IncSuccess, pbar.IncSuccess, pbar.IncFailed, pbar.IncSkipped, pbar.IncSkipped} {
+		if err := inc(); err != nil {
+			t.Fatalf("Error counting item: %s", err)
+		}
+	}
+
+	last := sink.updates[len(sink.updates)-1]
+	if last.Text != "3 ok, 1 failed, 2 skipped" {
+		t.Errorf("Expected the counts in the message, got %q", last.Text)
+	}
+	if last.Bar != "SSSFKK...." {
+		t.Errorf("Expected a segmented bar, got %q", last.Bar)
+	}
+	if last.Pos != 6 || last.Counts != (progress.Counts{Success: 3, Failed: 1, Skipped: 2}) {
+		t.Errorf("Expected 6 items counted, got %d and %+v", last.Pos, last.Counts)
+	}
+}
+
+func TestCountsSummary(t *testing.T) {
+	sink := &memSink{}
+	pbar := newProgress(t, sink, progress.WithTotal(2), progress.WithCompleteMsg(progress.DefaultCompleteMsg, false))
+
+	pbar.IncSuccess()
+	if err := pbar.IncFailed(); err != nil {
+		t.Fatalf("Error counting item: %s", err)
+	}
+	if last := sink.updates[len(sink.updates)-1]; !strings.HasSuffix(last.Text, ", 1 failed") {
+		t.Errorf("Expected the failures in the summary, got %q", last.Text)
+	}
+}
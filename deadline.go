@@ -0,0 +1,97 @@
+package progress
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// deadline returns when the task becomes overdue or the zero time if there's
+// no deadline. Time spent paused doesn't count towards MaxDuration, so the
+// deadline moves while the task is paused. p.mu must be held.
+func (p *Progress) deadline(now time.Time) time.Time {
+	deadline := p.Opts.Deadline
+	if p.Opts.MaxDuration > 0 {
+		if max := now.Add(p.Opts.MaxDuration - p.elapsed(now)); deadline.IsZero() || max.Before(deadline) {
+			deadline = max
+		}
+	}
+	return deadline
+}
+
+// rearmDeadline wakes deadlineLoop up to wait for the deadline again, e.g.
+// after Load has restored when the task started. p.mu must be held.
+func (p *Progress) rearmDeadline() {
+	select {
+	case p.rearm <- struct{}{}:
+	default: // Already woken up
+	}
+}
+
+// deadlineLoop waits for the deadline and shows the task as overdue unless it
+// has ended or Close or Reset is called first. The deadline is checked again
+// when the timer fires, since pausing moves it, and when rearmDeadline is
+// called.
+func (p *Progress) deadlineLoop(reset <-chan struct{}) {
+	for {
+		p.mu.Lock()
+		timer := time.NewTimer(time.Until(p.deadline(time.Now())))
+		p.mu.Unlock()
+
+		select {
+		case <-timer.C:
+			p.mu.Lock()
+			now := time.Now()
+			overdue := !now.Before(p.deadline(now))
+			p.mu.Unlock()
+			if overdue {
+				p.overdue()
+				return
+			}
+		case <-p.rearm:
+			timer.Stop()
+		case <-p.closed:
+			timer.Stop()
+			return
+		case <-reset:
+			timer.Stop()
+			return
+		}
+	}
+}
+
+// overdue re-renders the message now that the deadline has passed and sends
+// a notification if Options.OverdueNotify is set. If nothing has been posted
+// yet the first message shows the task as overdue and the notification is
+// sent once it's posted.
+func (p *Progress) overdue() {
+	p.sendMu.Lock()
+	defer p.sendMu.Unlock()
+	p.mu.Lock()
+
+	if p.done || p.lastPct == 100 {
+		p.mu.Unlock()
+		return
+	}
+	if p.id == "" {
+		p.overdueNotify = p.Opts.OverdueNotify
+		p.mu.Unlock()
+		return
+	}
+
+	p.resend = true
+	// Errors are logged and passed to Options.OnError by send
+	p.update(context.Background(), p.count.Load(), p.total())
+	id := p.id
+	p.mu.Unlock()
+
+	if p.Opts.OverdueNotify {
+		p.notifyOverdue(context.Background(), id)
+	}
+}
+
+// notifyOverdue sends the notification that the task is overdue. p.sendMu
+// must be held but not p.mu.
+func (p *Progress) notifyOverdue(ctx context.Context, id string) {
+	p.notify(ctx, id, fmt.Sprintf("%s is overdue", p.Opts.Task))
+}
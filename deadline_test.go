@@ -0,0 +1,115 @@
+package progress_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sfreiberg/progress"
+	"github.com/sfreiberg/progress/progresstest"
+)
+
+func TestDeadline(t *testing.T) {
+	pbar, sink := progresstest.New(t,
+		progress.WithTask("report"),
+		progress.WithMaxDuration(20*time.Millisecond, true),
+		progress.WithMentions("U123"),
+	)
+	defer pbar.Close()
+
+	if err := pbar.Update(10); err != nil {
+		t.Fatalf("Error updating progress bar: %s", err)
+	}
+
+	deadline := time.Now().Add(time.Second)
+	for len(sink.Notifications()) == 0 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	notes := sink.Notifications()
+	if len(notes) != 1 || notes[0].Text != "report is overdue" {
+		t.Fatalf("Expected an overdue notification, got %+v", notes)
+	}
+	last := sink.Last()
+	if last.Overdue == 0 || !strings.Contains(last.Text, "overdue by") || !strings.Contains(last.Bar, "🟥") {
+		t.Errorf("Expected the message to show the task is overdue, got %q", last.Text)
+	}
+}
+
+func TestDeadlineNotReached(t *testing.T) {
+	pbar, sink := progresstest.New(t, progress.WithDeadline(time.Now().Add(time.Hour), false))
+	defer pbar.Close()
+
+	if err := pbar.Update(10); err != nil {
+		t.Fatalf("Error updating progress bar: %s", err)
+	}
+	if last := sink.Last(); last.Overdue != 0 || strings.Contains(last.Bar, "🟥") {
+		t.Errorf("Expected the task to be on time, got %q", last.Text)
+	}
+}
+
+// waitForNotification waits up to a second for sink to receive a notification.
+func waitForNotification(sink *progresstest.Sink) []progresstest.Notification {
+	deadline := time.Now().Add(time.Second)
+	for len(sink.Notifications()) == 0 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	return sink.Notifications()
+}
+
+func TestDeadlineBeforeFirstPost(t *testing.T) {
+	pbar, sink := progresstest.New(t, progress.WithTask("report"), progress.WithMaxDuration(10*time.Millisecond, true))
+	defer pbar.Close()
+
+	time.Sleep(30 * time.Millisecond)
+	if err := pbar.Update(10); err != nil {
+		t.Fatalf("Error updating progress bar: %s", err)
+	}
+
+	notes := waitForNotification(sink)
+	if len(notes) != 1 || notes[0].ID != "1" || notes[0].Text != "report is overdue" {
+		t.Fatalf("Expected an overdue notification once the message was posted, got %+v", notes)
+	}
+	if sink.Posts()[0].Overdue == 0 {
+		t.Errorf("Expected the first message to show the task is overdue")
+	}
+}
+
+func TestDeadlineAfterLoad(t *testing.T) {
+	pbar, sink := progresstest.New(t, progress.WithTask("report"), progress.WithMaxDuration(time.Hour, true))
+	defer pbar.Close()
+
+	if err := pbar.Update(10); err != nil {
+		t.Fatalf("Error updating progress bar: %s", err)
+	}
+	saved := fmt.Sprintf(`{"message_ts":"1","start":%q,"pos":10,"total":100}`, time.Now().Add(-2*time.Hour).Format(time.RFC3339))
+	if err := pbar.Load(strings.NewReader(saved)); err != nil {
+		t.Fatalf("Error loading state: %s", err)
+	}
+
+	notes := waitForNotification(sink)
+	if len(notes) != 1 || notes[0].Text != "report is overdue" {
+		t.Fatalf("Expected an overdue notification for the restored start, got %+v", notes)
+	}
+}
+
+func TestDeadlinePaused(t *testing.T) {
+	pbar, sink := progresstest.New(t, progress.WithMaxDuration(30*time.Millisecond, false))
+	defer pbar.Close()
+
+	if err := pbar.Update(10); err != nil {
+		t.Fatalf("Error updating progress bar: %s", err)
+	}
+	if err := pbar.Pause(); err != nil {
+		t.Fatalf("Error pausing progress bar: %s", err)
+	}
+	time.Sleep(50 * time.Millisecond)
+	if err := pbar.Resume(); err != nil {
+		t.Fatalf("Error resuming progress bar: %s", err)
+	}
+
+	if last := sink.Last(); last.Overdue != 0 {
+		t.Errorf("Expected the time spent paused not to count, got overdue by %s", last.Overdue)
+	}
+}
@@ -0,0 +1,58 @@
+package progress
+
+import (
+	"context"
+	"strings"
+
+	"github.com/slack-go/slack"
+)
+
+// NewDMSink creates a Sink that sends the progress bar to user as a direct
+// message. user is a slack user ID or the email address of the user. The
+// conversation is opened when the first message is posted, which needs the
+// im:write scope and users:read.email to look up emails.
+func NewDMSink(token, user string, opts *Options) Sink {
+	if opts == nil {
+		opts = DefaultOptions("Unknown Task")
+	}
+
+	return &slackSink{
+		client: newSlackClient(token, opts),
+		user:   user,
+		opts:   opts,
+	}
+}
+
+// NewDM creates a new progress bar that's sent to user, a slack user ID or
+// email address, as a direct message. Progress is created with DefaultOptions
+// customized by opts.
+func NewDM(token, user string, opts ...Option) (*Progress, error) {
+	o := buildOptions(opts)
+	return NewWithSink(NewDMSink(token, user, o), o)
+}
+
+// openDM opens the direct message conversation with s.user, if there is one,
+// and posts to it from then on.
+func (s *slackSink) openDM(ctx context.Context) error {
+	if s.user == "" {
+		return nil
+	}
+
+	userID := s.user
+	if strings.Contains(userID, "@") {
+		user, err := s.client.GetUserByEmailContext(ctx, userID)
+		if err != nil {
+			return slackError(err)
+		}
+		userID = user.ID
+	}
+
+	channel, _, _, err := s.client.OpenConversationContext(ctx, &slack.OpenConversationParameters{Users: []string{userID}})
+	if err != nil {
+		return slackError(err)
+	}
+
+	s.channel = channel.ID
+	s.user = ""
+	return nil
+}
@@ -0,0 +1,54 @@
+package progress
+
+import (
+	"net/url"
+	"testing"
+)
+
+func TestDM(t *testing.T) {
+	var methods []string
+	sink, done := newTestSlackSink(t, DefaultOptions("backup"), func(method string, form url.Values) string {
+		methods = append(methods, method)
+		switch method {
+		case "users.lookupByEmail":
+			if form.Get("email") != "ada@example.com" {
+				t.Errorf("Expected a lookup of ada@example.com, got %v", form)
+			}
+			return `{"ok":true,"user":{"id":"U123"}}`
+		case "conversations.open":
+			if form.Get("users") != "U123" {
+				t.Errorf("Expected a conversation with U123, got %v", form)
+			}
+			return `{"ok":true,"channel":{"id":"D456"}}`
+		default:
+			if form.Get("channel") != "D456" {
+				t.Errorf("Expected a message to D456, got %v", form)
+			}
+			return `{"ok":true,"channel":"D456","ts":"1.2"}`
+		}
+	})
+	defer done()
+	sink.channel = ""
+	sink.user = "ada@example.com"
+
+	pbar, err := NewWithSink(sink, WithMinInterval(0))
+	if err != nil {
+		t.Fatalf("Error creating progress bar: %s", err)
+	}
+	for _, pos := range []int{50, 100} {
+		if err := pbar.Update(pos); err != nil {
+			t.Fatalf("Error updating progress bar: %s", err)
+		}
+	}
+
+	want := []string{"users.lookupByEmail", "conversations.open", "chat.postMessage", "chat.update"}
+	if len(methods) != len(want) {
+		t.Fatalf("Expected %v, got %v", want, methods)
+	}
+	for i := range want {
+		if methods[i] != want[i] {
+			t.Errorf("Expected %v, got %v", want, methods)
+			break
+		}
+	}
+}
@@ -0,0 +1,45 @@
+package progress
+
+import (
+	"context"
+	"io"
+	"net/http"
+)
+
+// Download GETs url, writes the response body to dst and advances p as the
+// bytes arrive. The total is set to the Content-Length of the response, if
+// the server sends one. The request is sent with Options.HTTPClient, or
+// http.DefaultClient if it's not set. Once the download is over the task is
+// ended with Done, so it fails if the download did. Download returns the
+// number of bytes written.
+func Download(ctx context.Context, url string, dst io.Writer, p *Progress) (int64, error) {
+	n, err := download(ctx, url, dst, p)
+	p.Done(err)
+	return n, err
+}
+
+func download(ctx context.Context, url string, dst io.Writer, p *Progress) (int64, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return 0, err
+	}
+
+	client := p.Opts.HTTPClient
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return 0, &StatusError{Code: resp.StatusCode, Status: resp.Status, Host: req.URL.Host}
+	}
+	if resp.ContentLength > 0 {
+		p.SetTotal64(resp.ContentLength)
+	}
+
+	return io.Copy(dst, p.NewProxyReader(resp.Body))
+}
@@ -0,0 +1,51 @@
+package progress_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/sfreiberg/progress"
+	"github.com/sfreiberg/progress/progresstest"
+)
+
+func TestDownload(t *testing.T) {
+	body := strings.Repeat("x", 64*1024)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/missing" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Length", strconv.Itoa(len(body)))
+		w.Write([]byte(body))
+	}))
+	defer srv.Close()
+
+	pbar, sink := progresstest.New(t)
+	var dst bytes.Buffer
+	n, err := progress.Download(context.Background(), srv.URL+"/file", &dst, pbar)
+	if err != nil {
+		t.Fatalf("Error downloading: %s", err)
+	}
+	if n != int64(len(body)) || dst.String() != body {
+		t.Errorf("Expected %d bytes, got %d", len(body), n)
+	}
+	if last := sink.Last(); !last.Complete || last.Total != int64(len(body)) {
+		t.Errorf("Expected the download to complete with the content length as total, got %d / %d", last.Pos, last.Total)
+	}
+
+	pbar, sink = progresstest.New(t)
+	_, err = progress.Download(context.Background(), srv.URL+"/missing", &dst, pbar)
+	var se *progress.StatusError
+	if !errors.As(err, &se) || se.Code != http.StatusNotFound {
+		t.Fatalf("Expected a status error, got %v", err)
+	}
+	if !sink.Last().Failed {
+		t.Errorf("Expected the task to fail")
+	}
+}
@@ -0,0 +1,56 @@
+package progress
+
+import (
+	"fmt"
+	"time"
+)
+
+// DurationFormatter formats the durations shown in messages, e.g. to
+// translate them.
+type DurationFormatter interface {
+	FormatDuration(d time.Duration) string
+}
+
+// DurationFormatterFunc is a function that implements DurationFormatter.
+type DurationFormatterFunc func(d time.Duration) string
+
+// FormatDuration calls f(d).
+func (f DurationFormatterFunc) FormatDuration(d time.Duration) string {
+	return f(d)
+}
+
+// DurationUnits is a DurationFormatter that shows the two largest units of a
+// duration with the given labels, e.g. "2 Std. 3 Min." with
+// DurationUnits{Hour: "Std.", Minute: "Min.", Second: "Sek."}.
+type DurationUnits struct {
+	Hour   string
+	Minute string
+	Second string
+}
+
+// FormatDuration implements DurationFormatter.
+func (u DurationUnits) FormatDuration(d time.Duration) string {
+	switch {
+	case d >= time.Hour:
+		d = d.Round(time.Minute)
+		return fmt.Sprintf("%d %s %d %s", d/time.Hour, u.Hour, d%time.Hour/time.Minute, u.Minute)
+	case d >= time.Minute:
+		d = d.Round(time.Second)
+		return fmt.Sprintf("%d %s %d %s", d/time.Minute, u.Minute, d%time.Minute/time.Second, u.Second)
+	default:
+		return fmt.Sprintf("%d %s", d.Round(time.Second)/time.Second, u.Second)
+	}
+}
+
+// duration formats d with Options.Durations if one is set.
+func (p *Progress) duration(d time.Duration) interface{} {
+	return formatDuration(p.Opts.Durations, d)
+}
+
+// formatDuration formats d with f or returns d itself if f is nil.
+func formatDuration(f DurationFormatter, d time.Duration) interface{} {
+	if f == nil {
+		return d
+	}
+	return f.FormatDuration(d)
+}
@@ -0,0 +1,53 @@
+package progress_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/sfreiberg/progress"
+)
+
+func TestDurationUnits(t *testing.T) {
+	german := progress.DurationUnits{Hour: "Std.", Minute: "Min.", Second: "Sek."}
+	tests := map[time.Duration]string{
+		2*time.Hour + 3*time.Minute + 20*time.Second: "2 Std. 3 Min.",
+		4*time.Minute + 12*time.Second:               "4 Min. 12 Sek.",
+		9 * time.Second:                              "9 Sek.",
+	}
+
+	for d, want := range tests {
+		if got := german.FormatDuration(d); got != want {
+			t.Errorf("Expected %s to be %q, got %q", d, want, got)
+		}
+	}
+}
+
+func TestDurations(t *testing.T) {
+	sink := &memSink{}
+	pbar := newProgress(t, sink,
+		progress.WithTemplate("{{ .Remaining }} verbleibend{{ if .Stalled }}, hängt{{ end }}"),
+		progress.WithDurations(progress.DurationFormatterFunc(func(d time.Duration) string { return "bald" })),
+	)
+
+	if err := pbar.Update(50); err != nil {
+		t.Fatalf("Error updating progress bar: %s", err)
+	}
+	if text := sink.posts[0].Text; text != "bald verbleibend" {
+		t.Errorf("Expected the formatted duration, got %q", text)
+	}
+}
+
+func TestDurationsHumanDuration(t *testing.T) {
+	sink := &memSink{}
+	pbar := newProgress(t, sink,
+		progress.WithTemplate("{{ humanDuration .Remaining }} verbleibend"),
+		progress.WithDurations(progress.DurationFormatterFunc(func(d time.Duration) string { return "bald" })),
+	)
+
+	if err := pbar.Update(50); err != nil {
+		t.Fatalf("Error updating progress bar: %s", err)
+	}
+	if text := sink.posts[0].Text; text != "bald verbleibend" {
+		t.Errorf("Expected the formatted duration, got %q", text)
+	}
+}
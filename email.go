@@ -0,0 +1,220 @@
+package progress
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"mime"
+	"mime/quotedprintable"
+	"net"
+	"net/smtp"
+	"strings"
+	"time"
+)
+
+// emailSink emails progress messages with SMTP. Emails can't be edited so
+// there's one when the task starts, a digest every time the progress bar
+// crosses a milestone and one when the task completes or fails.
+type emailSink struct {
+	addr      string
+	auth      smtp.Auth
+	from      string
+	to        []string
+	milestone milestone
+	done      bool // Whether the final email was sent
+
+	sendMail func(ctx context.Context, addr string, a smtp.Auth, from string, to []string, msg []byte) error
+}
+
+// NewEmailSink creates a Sink that emails the progress to to through the SMTP
+// server at addr, e.g. "smtp.example.com:587", for stakeholders who don't use
+// slack. auth may be nil, e.g. smtp.PlainAuth("", user, password, host). An
+// email is sent when the task starts, a digest every step percent and a
+// summary with the elapsed time, units processed and average rate when it
+// completes or fails. No digests are sent if step is 0.
+func NewEmailSink(addr string, auth smtp.Auth, from string, to []string, step int) Sink {
+	return &emailSink{
+		addr:      addr,
+		auth:      auth,
+		from:      from,
+		to:        to,
+		milestone: milestone{step: step},
+		sendMail:  sendMail,
+	}
+}
+
+// NewEmail creates a new progress bar that sends emails through the SMTP
+// server at addr with a digest every DefaultWebhookStep percent. Progress is
+// created with DefaultOptions customized by opts.
+func NewEmail(addr string, auth smtp.Auth, from string, to []string, opts ...Option) (*Progress, error) {
+	return NewWithSink(NewEmailSink(addr, auth, from, to, DefaultWebhookStep), opts...)
+}
+
+func (s *emailSink) Post(ctx context.Context, msg *Message) (string, error) {
+	subject := "Started: " + msg.Task
+	if msg.Complete || msg.Failed {
+		subject = s.finalSubject(msg)
+	}
+	if err := s.send(ctx, subject, msg); err != nil {
+		return "", err
+	}
+
+	// There's nothing to edit so any non empty id will do
+	return s.from, nil
+}
+
+func (s *emailSink) Update(ctx context.Context, id string, msg *Message) error {
+	switch {
+	case s.done:
+		return ErrSkipped
+	case msg.Complete || msg.Failed:
+		return s.send(ctx, s.finalSubject(msg), msg)
+	case s.milestone.step > 0 && s.milestone.crossed(msg):
+		return s.send(ctx, fmt.Sprintf("%s: %d%% complete", msg.Task, msg.Pct), msg)
+	}
+	return ErrSkipped
+}
+
+// finalSubject is the subject of the email sent when the task ends.
+func (s *emailSink) finalSubject(msg *Message) string {
+	if msg.Failed {
+		return "Failed: " + msg.Task
+	}
+	return "Completed: " + msg.Task
+}
+
+func (s *emailSink) send(ctx context.Context, subject string, msg *Message) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	var body bytes.Buffer
+	w := quotedprintable.NewWriter(&body)
+	w.Write([]byte(strings.ReplaceAll(emailText(msg), "\n", "\r\n")))
+	w.Close()
+
+	var email bytes.Buffer
+	fmt.Fprintf(&email, "From: %s\r\n", s.from)
+	fmt.Fprintf(&email, "To: %s\r\n", strings.Join(s.to, ", "))
+	fmt.Fprintf(&email, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", subject))
+	fmt.Fprintf(&email, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	email.WriteString("MIME-Version: 1.0\r\n")
+	email.WriteString("Content-Type: text/plain; charset=utf-8\r\n")
+	email.WriteString("Content-Transfer-Encoding: quoted-printable\r\n\r\n")
+	email.Write(body.Bytes())
+
+	if err := s.sendMail(ctx, s.addr, s.auth, s.from, s.to, email.Bytes()); err != nil {
+		return err
+	}
+
+	if s.milestone.step > 0 {
+		s.milestone.mark(msg)
+	}
+	s.done = msg.Complete || msg.Failed
+	return nil
+}
+
+// sendMail is smtp.SendMail with a context. The connection is dialed with ctx
+// and its deadline follows ctx, so a slow or unreachable server gives up when
+// ctx is cancelled or times out.
+func sendMail(ctx context.Context, addr string, a smtp.Auth, from string, to []string, msg []byte) error {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return err
+	}
+
+	conn, err := (&net.Dialer{}).DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+	// Unblock reads and writes that are waiting on the server when ctx is
+	// cancelled
+	stop := context.AfterFunc(ctx, func() { conn.SetDeadline(time.Now()) })
+	defer stop()
+
+	if err := smtpSend(conn, host, a, from, to, msg); err != nil {
+		var ne net.Error
+		if _, ok := ctx.Deadline(); ok && errors.As(err, &ne) && ne.Timeout() {
+			// The conn deadline can pass a moment before ctx is done
+			<-ctx.Done()
+		}
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return ctxErr
+		}
+		return err
+	}
+	return nil
+}
+
+// smtpSend sends msg over conn the way smtp.SendMail does, upgrading to TLS
+// if the server supports it.
+func smtpSend(conn net.Conn, host string, a smtp.Auth, from string, to []string, msg []byte) error {
+	c, err := smtp.NewClient(conn, host)
+	if err != nil {
+		return err
+	}
+	defer c.Close()
+
+	if ok, _ := c.Extension("STARTTLS"); ok {
+		if err := c.StartTLS(&tls.Config{ServerName: host}); err != nil {
+			return err
+		}
+	}
+	if a != nil {
+		if ok, _ := c.Extension("AUTH"); !ok {
+			return errors.New("smtp: server doesn't support AUTH")
+		}
+		if err := c.Auth(a); err != nil {
+			return err
+		}
+	}
+
+	if err := c.Mail(from); err != nil {
+		return err
+	}
+	for _, addr := range to {
+		if err := c.Rcpt(addr); err != nil {
+			return err
+		}
+	}
+	w, err := c.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(msg); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+	return c.Quit()
+}
+
+// emailText is the body of the email for msg: the rendered message followed
+// by the summary stats once the task has ended.
+func emailText(msg *Message) string {
+	text := msg.Text
+	if !msg.Complete && !msg.Failed {
+		return text
+	}
+
+	total, _ := comma(msg.Total)
+	pos, _ := comma(msg.Pos)
+	text += fmt.Sprintf("\n\nElapsed: %s\nProcessed: %s of %s", msg.Elapsed, pos, total)
+	if msg.AvgRate > 0 {
+		text += fmt.Sprintf("\nAverage rate: %.1f/s", msg.AvgRate)
+	}
+	if c := msg.Counts; c != (Counts{}) {
+		text += fmt.Sprintf("\nSucceeded: %d, failed: %d, skipped: %d", c.Success, c.Failed, c.Skipped)
+	}
+	if msg.Err != nil {
+		text += fmt.Sprintf("\nError: %s", msg.Err)
+	}
+	return text
+}
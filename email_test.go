@@ -0,0 +1,112 @@
+package progress
+
+import (
+	"context"
+	"errors"
+	"io"
+	"mime"
+	"mime/quotedprintable"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"strings"
+	"testing"
+	"time"
+)
+
+// sentEmail is an email passed to emailSink.sendMail.
+type sentEmail struct {
+	Subject string
+	Body    string
+}
+
+func newTestEmailSink(t *testing.T, step int) (*emailSink, *[]sentEmail) {
+	var sent []sentEmail
+	sink := NewEmailSink("smtp.example.com:587", nil, "bot@example.com", []string{"cto@example.com"}, step).(*emailSink)
+	sink.sendMail = func(ctx context.Context, addr string, a smtp.Auth, from string, to []string, msg []byte) error {
+		m, err := mail.ReadMessage(strings.NewReader(string(msg)))
+		if err != nil {
+			t.Fatalf("Error parsing email: %s", err)
+		}
+		subject, _ := new(mime.WordDecoder).DecodeHeader(m.Header.Get("Subject"))
+		body, _ := io.ReadAll(quotedprintable.NewReader(m.Body))
+		sent = append(sent, sentEmail{Subject: subject, Body: string(body)})
+		return nil
+	}
+	return sink, &sent
+}
+
+func TestEmail(t *testing.T) {
+	sink, sent := newTestEmailSink(t, 25)
+	pbar, err := NewWithSink(sink, WithTask("Migrate ✉"), WithMinInterval(0))
+	if err != nil {
+		t.Fatalf("Error creating progress bar: %s", err)
+	}
+	for i := 1; i <= 100; i++ {
+		if err := pbar.Update(i); err != nil {
+			t.Fatalf("Error updating progress bar: %s", err)
+		}
+	}
+
+	var subjects []string
+	for _, email := range *sent {
+		subjects = append(subjects, email.Subject)
+	}
+	want := "Started: Migrate ✉|Migrate ✉: 25% complete|Migrate ✉: 50% complete|Migrate ✉: 75% complete|Completed: Migrate ✉"
+	if got := strings.Join(subjects, "|"); got != want {
+		t.Errorf("Expected emails %q, got %q", want, got)
+	}
+	if last := (*sent)[len(*sent)-1].Body; !strings.Contains(last, "Processed: 100 of 100") || !strings.Contains(last, "Average rate:") {
+		t.Errorf("Expected the summary stats in the final email, got %q", last)
+	}
+}
+
+func TestEmailFailed(t *testing.T) {
+	sink, sent := newTestEmailSink(t, 0)
+	pbar, err := NewWithSink(sink, WithTask("Migrate"), WithMinInterval(0))
+	if err != nil {
+		t.Fatalf("Error creating progress bar: %s", err)
+	}
+	for i := 1; i <= 60; i++ {
+		pbar.Update(i)
+	}
+	if err := pbar.Fail(errors.New("disk full")); err != nil {
+		t.Fatalf("Error failing progress bar: %s", err)
+	}
+
+	if len(*sent) != 2 {
+		t.Fatalf("Expected a start and a failure email without digests, got %+v", *sent)
+	}
+	if last := (*sent)[1]; last.Subject != "Failed: Migrate" || !strings.Contains(last.Body, "Error: disk full") {
+		t.Errorf("Expected the failure email, got %+v", last)
+	}
+}
+
+func TestSendMailTimeout(t *testing.T) {
+	// A server that accepts connections but never answers
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Error listening: %s", err)
+	}
+	defer l.Close()
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			defer conn.Close()
+		}
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	err = sendMail(ctx, l.Addr().String(), nil, "bot@example.com", []string{"cto@example.com"}, []byte("Subject: hi\r\n\r\nhi"))
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected the deadline to be exceeded, got %v", err)
+	}
+	if took := time.Since(start); took > time.Second {
+		t.Errorf("Expected sendMail to give up with the context, took %s", took)
+	}
+}
@@ -0,0 +1,62 @@
+package progress
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+)
+
+// Environment variables read by NewFromEnv.
+const (
+	EnvToken   = "SLACK_TOKEN"    // Bot token. The bar is drawn on the terminal without one.
+	EnvChannel = "SLACK_CHANNEL"  // Channel ID or #name, required with a token
+	EnvTask    = "PROGRESS_TASK"  // Options.Task
+	EnvWidth   = "PROGRESS_WIDTH" // Options.Width
+	EnvFill    = "PROGRESS_FILL"  // Options.Fill
+	EnvEmpty   = "PROGRESS_EMPTY" // Options.Empty
+	EnvTheme   = "PROGRESS_THEME" // Options.Theme
+)
+
+// NewFromEnv creates a new progress bar configured by environment variables,
+// which is handy in containers and CI. SLACK_CHANNEL is required when
+// SLACK_TOKEN is set. Without a token the progress bar is drawn on the
+// terminal like New does. PROGRESS_TASK, PROGRESS_WIDTH, PROGRESS_FILL,
+// PROGRESS_EMPTY and PROGRESS_THEME are optional and override opts when
+// they're set. Progress is created with DefaultOptions customized by opts.
+func NewFromEnv(opts ...Option) (*Progress, error) {
+	token, channel := os.Getenv(EnvToken), os.Getenv(EnvChannel)
+	if token != "" && channel == "" {
+		return nil, errors.New(EnvChannel + " isn't set")
+	}
+
+	o := buildOptions(opts)
+	if err := envOptions(o); err != nil {
+		return nil, err
+	}
+	return New(token, channel, o)
+}
+
+// envOptions sets the fields of o that have an environment variable.
+func envOptions(o *Options) error {
+	if task := os.Getenv(EnvTask); task != "" {
+		o.Task = task
+	}
+	if width := os.Getenv(EnvWidth); width != "" {
+		n, err := strconv.Atoi(width)
+		if err != nil || n <= 0 {
+			return fmt.Errorf("Invalid %s %q", EnvWidth, width)
+		}
+		o.Width = n
+	}
+	if fill := os.Getenv(EnvFill); fill != "" {
+		o.Fill = fill
+	}
+	if empty := os.Getenv(EnvEmpty); empty != "" {
+		o.Empty = empty
+	}
+	if theme := os.Getenv(EnvTheme); theme != "" {
+		o.Theme = theme
+	}
+	return nil
+}
@@ -0,0 +1,34 @@
+package progress_test
+
+import (
+	"testing"
+
+	"github.com/sfreiberg/progress"
+)
+
+func TestNewFromEnv(t *testing.T) {
+	t.Setenv(progress.EnvToken, "xoxb-token")
+	t.Setenv(progress.EnvChannel, "C123")
+	t.Setenv(progress.EnvTask, "deploy")
+	t.Setenv(progress.EnvWidth, "20")
+	t.Setenv(progress.EnvFill, "#")
+
+	pbar, err := progress.NewFromEnv(progress.WithWidth(5), progress.WithEmpty("."))
+	if err != nil {
+		t.Fatalf("Error creating progress bar: %s", err)
+	}
+	if o := pbar.Opts; o.Task != "deploy" || o.Width != 20 || o.Fill != "#" || o.Empty != "." {
+		t.Errorf("Expected the environment to override the options, got %q %d %q %q", o.Task, o.Width, o.Fill, o.Empty)
+	}
+
+	t.Setenv(progress.EnvWidth, "wide")
+	if _, err := progress.NewFromEnv(); err == nil {
+		t.Error("Expected an error for an invalid width")
+	}
+
+	t.Setenv(progress.EnvWidth, "")
+	t.Setenv(progress.EnvChannel, "")
+	if _, err := progress.NewFromEnv(); err == nil {
+		t.Error("Expected an error without a channel")
+	}
+}
@@ -0,0 +1,65 @@
+package progress
+
+import (
+	"context"
+
+	"github.com/slack-go/slack"
+)
+
+// ephemeralSink posts progress messages that only Options.EphemeralUser can
+// see. Slack can't edit ephemeral messages with chat.update, so messages are
+// only sent every DefaultWebhookStep percent. With Options.ResponseURL the
+// message is replaced through the response url, which slack allows five
+// times, otherwise a new ephemeral message is posted.
+type ephemeralSink struct {
+	slack     *slackSink
+	milestone milestone
+}
+
+func (s *ephemeralSink) Post(ctx context.Context, msg *Message) (string, error) {
+	ts, err := s.send(ctx, msg, false)
+	if err != nil {
+		return "", err
+	}
+
+	// Response urls don't return a timestamp but an id is still needed
+	if ts == "" {
+		ts = s.slack.opts.ResponseURL
+	}
+	return ts, nil
+}
+
+func (s *ephemeralSink) Update(ctx context.Context, ts string, msg *Message) error {
+	if !s.milestone.crossed(msg) {
+		return ErrSkipped
+	}
+
+	_, err := s.send(ctx, msg, true)
+	return err
+}
+
+// send posts msg or, if replace is true and there's a response url, replaces
+// the message posted earlier.
+func (s *ephemeralSink) send(ctx context.Context, msg *Message, replace bool) (string, error) {
+	if err := s.slack.refreshToken(ctx); err != nil {
+		return "", err
+	}
+
+	var ts string
+	var err error
+
+	switch {
+	case s.slack.opts.ResponseURL != "" && replace:
+		_, _, _, err = s.slack.client.SendMessageContext(ctx, s.slack.channel, s.slack.msgOptions(msg), slack.MsgOptionReplaceOriginal(s.slack.opts.ResponseURL))
+	case s.slack.opts.ResponseURL != "":
+		_, _, _, err = s.slack.client.SendMessageContext(ctx, s.slack.channel, s.slack.msgOptions(msg), slack.MsgOptionResponseURL(s.slack.opts.ResponseURL, slack.ResponseTypeEphemeral))
+	default:
+		ts, err = s.slack.client.PostEphemeralContext(ctx, s.slack.channel, s.slack.opts.EphemeralUser, s.slack.msgOptions(msg), slack.MsgOptionAsUser(s.slack.opts.AsUser))
+	}
+	if err != nil {
+		return "", slackError(err)
+	}
+
+	s.milestone.mark(msg)
+	return ts, nil
+}
@@ -0,0 +1,76 @@
+package progress
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+)
+
+func TestEphemeral(t *testing.T) {
+	var methods []string
+	sink, done := newTestSlackSink(t, DefaultOptions("deploy"), func(method string, form url.Values) string {
+		methods = append(methods, method)
+		if form.Get("user") != "U123" {
+			t.Errorf("Expected an ephemeral message for U123, got %v", form)
+		}
+		return `{"ok":true,"message_ts":"1.2"}`
+	})
+	defer done()
+	sink.opts.EphemeralUser = "U123"
+
+	pbar, err := NewWithSink(&ephemeralSink{slack: sink, milestone: milestone{step: DefaultWebhookStep}}, WithMinInterval(0))
+	if err != nil {
+		t.Fatalf("Error creating progress bar: %s", err)
+	}
+	for _, pos := range []int{10, 20, 30, 40, 100} {
+		if err := pbar.Update(pos); err != nil {
+			t.Fatalf("Error updating progress bar: %s", err)
+		}
+	}
+
+	// 10%, 30% crossing 25% and 100%
+	if len(methods) != 3 || methods[0] != "chat.postEphemeral" {
+		t.Errorf("Expected 3 ephemeral messages, got %v", methods)
+	}
+}
+
+func TestEphemeralResponseURL(t *testing.T) {
+	var replaced []bool
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			ResponseType    string `json:"response_type"`
+			ReplaceOriginal bool   `json:"replace_original"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Errorf("Error decoding response: %s", err)
+		}
+		replaced = append(replaced, body.ReplaceOriginal)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"ok":true}`))
+	}))
+	defer srv.Close()
+
+	sink, done := newTestSlackSink(t, DefaultOptions("deploy"), func(method string, form url.Values) string {
+		t.Errorf("Expected no api calls, got %s", method)
+		return `{"ok":true}`
+	})
+	defer done()
+	sink.opts.EphemeralUser = "U123"
+	sink.opts.ResponseURL = srv.URL
+
+	pbar, err := NewWithSink(&ephemeralSink{slack: sink, milestone: milestone{step: DefaultWebhookStep}}, WithMinInterval(0))
+	if err != nil {
+		t.Fatalf("Error creating progress bar: %s", err)
+	}
+	for _, pos := range []int{10, 50, 100} {
+		if err := pbar.Update(pos); err != nil {
+			t.Fatalf("Error updating progress bar: %s", err)
+		}
+	}
+
+	if len(replaced) != 3 || replaced[0] || !replaced[1] || !replaced[2] {
+		t.Errorf("Expected a response followed by 2 replacements, got %v", replaced)
+	}
+}
@@ -0,0 +1,114 @@
+package progress
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+var (
+	// ErrRateLimited matches every *RateLimitedError with errors.Is. Use
+	// errors.As to get the RetryAfter duration.
+	ErrRateLimited = errors.New("Rate limited")
+	// ErrCantEdit is returned by Sink.Update when the message can no longer
+	// be edited, e.g. because it was deleted or is too old. Progress posts a
+	// new message and keeps updating that one instead.
+	ErrCantEdit = errors.New("Message can't be edited")
+	// ErrSkipped is returned by Sink.Update when the sink deliberately
+	// didn't send the message, e.g. a webhook that only posts milestones.
+	// Progress doesn't count it as sent, so the next update is sent to the
+	// sink too.
+	ErrSkipped = errors.New("Message skipped")
+	// ErrMessageNotFound is returned when the message doesn't exist anymore,
+	// usually because someone deleted it. It also matches ErrCantEdit.
+	ErrMessageNotFound = errors.New("Message not found")
+	// ErrChannelNotFound is returned when the channel doesn't exist or a
+	// channel name like #deploys can't be found in the workspace.
+	ErrChannelNotFound = errors.New("Channel not found")
+	// ErrNotInChannel is returned when the bot isn't a member of the channel
+	// it's supposed to post to. Invite it with /invite.
+	ErrNotInChannel = errors.New("Not a member of the channel")
+)
+
+// slackErrors maps the error codes returned by the slack API to the errors
+// they match.
+var slackErrors = map[string][]error{
+	"ratelimited":         {ErrRateLimited},
+	"message_not_found":   {ErrMessageNotFound, ErrCantEdit},
+	"cant_update_message": {ErrCantEdit},
+	"edit_window_closed":  {ErrCantEdit},
+	"channel_not_found":   {ErrChannelNotFound},
+	"not_in_channel":      {ErrNotInChannel},
+}
+
+// SlackError is returned when the slack API responds with an error code. It
+// matches ErrChannelNotFound, ErrNotInChannel, ErrMessageNotFound, ErrCantEdit
+// and ErrRateLimited with errors.Is and unwraps to the error returned by the
+// slack client. The ratelimited code is returned as a *RateLimitedError
+// wrapping the SlackError, so it's waited out like HTTP 429.
+type SlackError struct {
+	Code string // The error code, e.g. channel_not_found
+	Err  error  // The error returned by the slack client
+}
+
+func (e *SlackError) Error() string {
+	return fmt.Sprintf("Slack error: %s", e.Code)
+}
+
+func (e *SlackError) Unwrap() error {
+	return e.Err
+}
+
+// Is reports whether the error code matches target.
+func (e *SlackError) Is(target error) bool {
+	for _, err := range slackErrors[e.Code] {
+		if err == target {
+			return true
+		}
+	}
+	return false
+}
+
+// RateLimitedError is returned by a Sink when the chat system asked us to
+// slow down. Progress skips updates until RetryAfter has passed, except for
+// the final message which waits.
+type RateLimitedError struct {
+	RetryAfter time.Duration
+	Err        error // The underlying error, e.g. a *SlackError with code ratelimited. Optional.
+}
+
+func (e *RateLimitedError) Error() string {
+	return fmt.Sprintf("Rate limited, retry after %s", e.RetryAfter)
+}
+
+// Is reports whether target is ErrRateLimited.
+func (e *RateLimitedError) Is(target error) bool {
+	return target == ErrRateLimited
+}
+
+func (e *RateLimitedError) Unwrap() error {
+	return e.Err
+}
+
+// StatusError is returned by a Sink when the chat system responded with an
+// unexpected HTTP status. 5xx responses are retried.
+type StatusError struct {
+	Code   int    // HTTP status code
+	Status string // HTTP status line, e.g. "503 Service Unavailable"
+	Host   string // The host that responded
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("Unexpected response from %s: %s", e.Host, e.Status)
+}
+
+// TooLongError is returned when the rendered message is longer than
+// Options.MaxLength even after the log and status have been dropped.
+type TooLongError struct {
+	Length int // Length of the rendered message in characters
+	Max    int // Options.MaxLength
+}
+
+func (e *TooLongError) Error() string {
+	return fmt.Sprintf("Message is too long: %d characters, the maximum is %d", e.Length, e.Max)
+}
@@ -0,0 +1,143 @@
+package progress
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"time"
+)
+
+const (
+	pagerDutyURL = "https://events.pagerduty.com/v2/enqueue"
+	opsgenieURL  = "https://api.opsgenie.com/v2/alerts"
+)
+
+// Escalator raises an alert in an incident management tool when a task fails
+// or stalls, because a message in chat is easy to miss at 3am. msg is the
+// failure message, with Failed and Err set, or the stall, with Stalled set.
+type Escalator interface {
+	Escalate(ctx context.Context, msg *Message) error
+}
+
+// escalate raises an alert for msg with Options.Escalator, if any. Errors are
+// logged and passed to Options.OnError. p.mu must not be held.
+func (p *Progress) escalate(ctx context.Context, msg *Message) {
+	if p.Opts.Escalator == nil {
+		return
+	}
+
+	if err := p.Opts.Escalator.Escalate(ctx, msg); err != nil {
+		p.log(ctx, slog.LevelError, "Error escalating", "error", err)
+		if p.Opts.OnError != nil {
+			p.Opts.OnError(err)
+		}
+	}
+}
+
+// escalationSummary describes the failure or stall in msg in one line.
+func escalationSummary(msg *Message) string {
+	if msg.Failed {
+		return fmt.Sprintf("%s failed after %s at %d%%: %v", msg.Task, msg.Elapsed, msg.Pct, msg.Err)
+	}
+	return fmt.Sprintf("%s has stalled for %s at %d%%", msg.Task, msg.Stalled, msg.Pct)
+}
+
+// escalationDetails are the fields of msg attached to alerts.
+func escalationDetails(msg *Message) map[string]string {
+	details := map[string]string{
+		"task":    msg.Task,
+		"elapsed": msg.Elapsed.String(),
+		"percent": fmt.Sprintf("%d%%", msg.Pct),
+	}
+	if msg.Phase != "" {
+		details["phase"] = msg.Phase
+	}
+	if msg.Status != "" {
+		details["status"] = msg.Status
+	}
+	if msg.Err != nil {
+		details["error"] = msg.Err.Error()
+	}
+	if msg.Stalled > 0 {
+		details["stalled"] = msg.Stalled.String()
+	}
+	return details
+}
+
+// pagerDuty triggers PagerDuty incidents with the Events API v2.
+type pagerDuty struct {
+	url        string
+	routingKey string
+}
+
+// NewPagerDuty creates an Escalator that triggers a PagerDuty incident with
+// the task, elapsed time and last status. routingKey is the integration key
+// of an Events API v2 integration on the service. Failures are triggered with
+// the error severity and stalls with the warning severity. Alerts for the same
+// task are grouped into one incident.
+func NewPagerDuty(routingKey string) Escalator {
+	return &pagerDuty{url: pagerDutyURL, routingKey: routingKey}
+}
+
+func (e *pagerDuty) Escalate(ctx context.Context, msg *Message) error {
+	severity := "warning"
+	if msg.Failed {
+		severity = "error"
+	}
+	source, _ := os.Hostname()
+	if source == "" {
+		source = "progress"
+	}
+
+	payload := map[string]interface{}{
+		"routing_key":  e.routingKey,
+		"event_action": "trigger",
+		"dedup_key":    "progress/" + msg.Task,
+		"payload": map[string]interface{}{
+			"summary":        escalationSummary(msg),
+			"source":         source,
+			"severity":       severity,
+			"timestamp":      time.Now().UTC().Format(time.RFC3339),
+			"custom_details": escalationDetails(msg),
+		},
+	}
+	return postJSON(ctx, e.url, payload, nil)
+}
+
+// opsgenie creates Opsgenie alerts with the Alert API.
+type opsgenie struct {
+	url    string
+	apiKey string
+}
+
+// NewOpsgenie creates an Escalator that creates an Opsgenie alert with the
+// task, elapsed time and last status. apiKey is the key of an API
+// integration. Failures get the P2 priority and stalls P3. Alerts for the
+// same task are deduplicated by Opsgenie.
+func NewOpsgenie(apiKey string) Escalator {
+	return &opsgenie{url: opsgenieURL, apiKey: apiKey}
+}
+
+func (e *opsgenie) Escalate(ctx context.Context, msg *Message) error {
+	priority := "P3"
+	if msg.Failed {
+		priority = "P2"
+	}
+
+	summary := escalationSummary(msg)
+	if r := []rune(summary); len(r) > 130 { // Opsgenie's limit for the message
+		summary = string(r[:129]) + "…"
+	}
+
+	payload := map[string]interface{}{
+		"message":     summary,
+		"alias":       "progress/" + msg.Task,
+		"description": msg.Text,
+		"details":     escalationDetails(msg),
+		"priority":    priority,
+	}
+	header := http.Header{"Authorization": {"GenieKey " + e.apiKey}}
+	return sendJSON(ctx, http.MethodPost, e.url, header, payload, nil)
+}
@@ -0,0 +1,104 @@
+package progress
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// escalationServer records the JSON requests it receives.
+func escalationServer(t *testing.T) (*httptest.Server, chan map[string]interface{}) {
+	reqs := make(chan map[string]interface{}, 10)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var payload map[string]interface{}
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			t.Errorf("Error decoding alert: %s", err)
+		}
+		payload["authorization"] = r.Header.Get("Authorization")
+		reqs <- payload
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	t.Cleanup(srv.Close)
+	return srv, reqs
+}
+
+func TestPagerDutyOnFail(t *testing.T) {
+	srv, reqs := escalationServer(t)
+	pd := NewPagerDuty("key").(*pagerDuty)
+	pd.url = srv.URL
+
+	pbar, err := NewWithSink(NewTerminalSink(io.Discard), WithTask("migrate"), WithEscalation(pd))
+	if err != nil {
+		t.Fatalf("Error creating progress bar: %s", err)
+	}
+	pbar.Update(40)
+	pbar.SetStatus("copying users")
+	if err := pbar.Fail(errors.New("disk full")); err != nil {
+		t.Fatalf("Error failing progress bar: %s", err)
+	}
+
+	req := <-reqs
+	payload := req["payload"].(map[string]interface{})
+	details := payload["custom_details"].(map[string]interface{})
+	if req["routing_key"] != "key" || req["event_action"] != "trigger" || payload["severity"] != "error" {
+		t.Errorf("Expected an error to be triggered, got %v", req)
+	}
+	if summary := payload["summary"].(string); !strings.HasPrefix(summary, "migrate failed after") || !strings.HasSuffix(summary, "at 40%: disk full") {
+		t.Errorf("Expected the failure in the summary, got %q", summary)
+	}
+	if details["status"] != "copying users" {
+		t.Errorf("Expected the last status in the details, got %v", details)
+	}
+}
+
+func TestOpsgenieOnStall(t *testing.T) {
+	srv, reqs := escalationServer(t)
+	og := NewOpsgenie("key").(*opsgenie)
+	og.url = srv.URL
+
+	pbar, err := NewWithSink(NewTerminalSink(io.Discard), WithTask("backup"), WithStall(20*time.Millisecond, false), WithEscalation(og))
+	if err != nil {
+		t.Fatalf("Error creating progress bar: %s", err)
+	}
+	defer pbar.Close()
+	pbar.Update(30)
+
+	select {
+	case req := <-reqs:
+		if req["authorization"] != "GenieKey key" || req["priority"] != "P3" || req["alias"] != "progress/backup" {
+			t.Errorf("Expected a P3 alert for the stall, got %v", req)
+		}
+		if msg := req["message"].(string); !strings.HasPrefix(msg, "backup has stalled for") {
+			t.Errorf("Expected the stall in the message, got %q", msg)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Expected the stall to be escalated")
+	}
+}
+
+func TestEscalationError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer srv.Close()
+
+	var errs []error
+	pd := NewPagerDuty("key").(*pagerDuty)
+	pd.url = srv.URL
+	pbar, err := NewWithSink(NewTerminalSink(io.Discard), WithEscalation(pd), WithHooks(nil, nil, nil, func(err error) { errs = append(errs, err) }))
+	if err != nil {
+		t.Fatalf("Error creating progress bar: %s", err)
+	}
+
+	if err := pbar.Fail(errors.New("boom")); err != nil {
+		t.Errorf("Expected escalation errors not to be returned, got %s", err)
+	}
+	if len(errs) != 1 {
+		t.Errorf("Expected the escalation error to be passed to OnError, got %v", errs)
+	}
+}
@@ -0,0 +1,90 @@
+package progress
+
+import "time"
+
+// Estimator estimates the time remaining until a task completes. Estimate is
+// called every time the progress bar is sent with the current position, the
+// total and the time elapsed since the task began running.
+type Estimator interface {
+	Estimate(pos, total int64, elapsed time.Duration) time.Duration
+}
+
+// LinearEstimator assumes the task progresses at the same average rate it has
+// since it began running. This is the default.
+type LinearEstimator struct{}
+
+// Estimate extrapolates the elapsed time to the total.
+func (LinearEstimator) Estimate(pos, total int64, elapsed time.Duration) time.Duration {
+	if pos <= 0 {
+		return 0
+	}
+
+	est := time.Duration(float64(elapsed) / float64(pos) * float64(total))
+	return est - elapsed
+}
+
+// EWMAEstimator estimates the time remaining from an exponentially weighted
+// moving average of the rate of progress, so it follows tasks whose
+// throughput changes over time. Create one with NewEWMAEstimator.
+type EWMAEstimator struct {
+	alpha       float64       // Weight of the newest sample
+	rate        float64       // Average units per nanosecond
+	lastPos     int64         // Position of the previous sample
+	lastElapsed time.Duration // Elapsed time of the previous sample
+}
+
+// NewEWMAEstimator creates an EWMAEstimator. alpha is the weight (0-1) given
+// to the newest sample of the rate. Higher values react faster to changes in
+// throughput, lower values give steadier estimates. 0.1 is a good start.
+func NewEWMAEstimator(alpha float64) *EWMAEstimator {
+	return &EWMAEstimator{alpha: alpha}
+}
+
+// Estimate updates the average rate with the progress made since the last
+// call and divides the remaining units by it. Calls that don't move the
+// position leave the average alone, so idle time counts against the rate.
+func (e *EWMAEstimator) Estimate(pos, total int64, elapsed time.Duration) time.Duration {
+	if pos != e.lastPos {
+		dpos, dt := pos-e.lastPos, elapsed-e.lastElapsed
+		if dpos > 0 && dt > 0 {
+			rate := float64(dpos) / float64(dt)
+			if e.rate == 0 {
+				e.rate = rate
+			} else {
+				e.rate = e.alpha*rate + (1-e.alpha)*e.rate
+			}
+		}
+		e.lastPos, e.lastElapsed = pos, elapsed
+	}
+	return e.peek(pos, total, elapsed)
+}
+
+// peek divides the remaining units by the average rate without adding a
+// sample.
+func (e *EWMAEstimator) peek(pos, total int64, elapsed time.Duration) time.Duration {
+	if e.rate == 0 {
+		return 0
+	}
+	return time.Duration(float64(total-pos) / e.rate)
+}
+
+// reset forgets every sample, e.g. when Progress.Reset starts a new task.
+func (e *EWMAEstimator) reset() {
+	e.rate, e.lastPos, e.lastElapsed = 0, 0, 0
+}
+
+// fresh returns a new EWMAEstimator with the same alpha and no samples.
+func (e *EWMAEstimator) fresh() statefulEstimator {
+	return NewEWMAEstimator(e.alpha)
+}
+
+// statefulEstimator is implemented by estimators that learn from every call
+// to Estimate. Read-only callers such as Progress.Snapshot use peek so how
+// often the progress bar is read doesn't change the estimate, and progress
+// bars created from the same options each get a fresh copy.
+type statefulEstimator interface {
+	Estimator
+	peek(pos, total int64, elapsed time.Duration) time.Duration
+	reset()
+	fresh() statefulEstimator
+}
@@ -0,0 +1,67 @@
+package progress_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/sfreiberg/progress"
+	"github.com/sfreiberg/progress/progresstest"
+)
+
+func TestLinearEstimator(t *testing.T) {
+	var est progress.LinearEstimator
+
+	if got := est.Estimate(25, 100, time.Minute); got != 3*time.Minute {
+		t.Errorf("Expected 3m remaining, got %s", got)
+	}
+	if got := est.Estimate(0, 100, time.Minute); got != 0 {
+		t.Errorf("Expected 0 remaining without progress, got %s", got)
+	}
+}
+
+func TestEWMAEstimator(t *testing.T) {
+	est := progress.NewEWMAEstimator(0.5)
+
+	// 10 units per second for the first 5 seconds
+	if got := est.Estimate(50, 100, 5*time.Second); got != 5*time.Second {
+		t.Errorf("Expected 5s remaining, got %s", got)
+	}
+
+	// Then the task slows down to 2 units per second. The average rate is
+	// now 6 units per second.
+	if got := est.Estimate(70, 100, 15*time.Second); got != 5*time.Second {
+		t.Errorf("Expected 5s remaining, got %s", got)
+	}
+
+	// A linear estimate would still be optimistic
+	if got := (progress.LinearEstimator{}).Estimate(70, 100, 15*time.Second); got >= 7*time.Second {
+		t.Errorf("Expected linear estimate to be optimistic, got %s", got)
+	}
+}
+
+func TestEWMAEstimatorIgnoresIdleCalls(t *testing.T) {
+	est := progress.NewEWMAEstimator(0.5)
+
+	est.Estimate(50, 100, 5*time.Second)
+	// Reading the estimate again without progress mustn't reset the sample
+	// window, so the idle time still counts against the next sample.
+	est.Estimate(50, 100, 10*time.Second)
+	if got := est.Estimate(70, 100, 15*time.Second); got != 5*time.Second {
+		t.Errorf("Expected 5s remaining, got %s", got)
+	}
+}
+
+func TestEWMAEstimatorReset(t *testing.T) {
+	est := progress.NewEWMAEstimator(0.5)
+	pbar, _ := progresstest.New(t, progress.WithEstimator(est))
+
+	if err := pbar.Update(50); err != nil {
+		t.Fatalf("Error updating progress bar: %s", err)
+	}
+	pbar.Reset("next")
+
+	// 10 units per second is the first sample of the new task
+	if got := est.Estimate(10, 100, time.Second); got != 9*time.Second {
+		t.Errorf("Expected 9s remaining after Reset, got %s", got)
+	}
+}
@@ -10,7 +10,30 @@ func ExampleProgress() {
 	token := "super-secret-slack-token"
 	channel := "demo"
 
-	pbar := progress.New(token, channel, nil)
+	pbar, err := progress.New(token, channel, nil)
+	if err != nil {
+		log.Fatalf("Error creating progress bar: %s\n", err)
+	}
+
+	for i := 0; i <= pbar.Opts.TotalUnits; i++ {
+		if err := pbar.Update(i); err != nil {
+			log.Printf("Error updating progress bar: %s\n", err)
+		}
+	}
+}
+
+func ExampleNew() {
+	token := "super-secret-slack-token"
+	channel := "demo"
+
+	pbar, err := progress.New(token, channel,
+		progress.WithTask("deploy"),
+		progress.WithWidth(20),
+		progress.WithFill("🟩"),
+	)
+	if err != nil {
+		log.Fatalf("Error creating progress bar: %s\n", err)
+	}
 
 	for i := 0; i <= pbar.Opts.TotalUnits; i++ {
 		if err := pbar.Update(i); err != nil {
@@ -0,0 +1,124 @@
+package progress
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+)
+
+// funcs are the functions available in every message template.
+var funcs = template.FuncMap{
+	"humanBytes":    humanBytes,
+	"humanDuration": humanDuration,
+	"comma":         comma,
+	"slackDate":     slackDate,
+}
+
+// humanBytes formats a number of bytes using binary units, e.g. 1534217728
+// becomes "1.4 GiB".
+func humanBytes(v interface{}) (string, error) {
+	n, err := toFloat(v)
+	if err != nil {
+		return "", err
+	}
+
+	const unit = 1024
+	if math.Abs(n) < unit {
+		return fmt.Sprintf("%.0f B", n), nil
+	}
+
+	exp := 0
+	for math.Abs(n) >= unit && exp < 6 {
+		n /= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", n, "KMGTPE"[exp-1]), nil
+}
+
+// humanDuration formats a duration with at most two units, e.g. "2h3m",
+// "4m12s" or "9s". Durations that were already formatted because
+// Options.Durations is set are returned as they are.
+func humanDuration(v interface{}) (string, error) {
+	switch v := v.(type) {
+	case time.Duration:
+		return formatHumanDuration(v), nil
+	case string:
+		return v, nil
+	default:
+		return "", fmt.Errorf("Invalid duration %v of type %T", v, v)
+	}
+}
+
+// formatHumanDuration formats d for humanDuration.
+func formatHumanDuration(d time.Duration) string {
+	switch {
+	case d >= time.Hour:
+		d = d.Round(time.Minute)
+		return fmt.Sprintf("%dh%dm", d/time.Hour, d%time.Hour/time.Minute)
+	case d >= time.Minute:
+		d = d.Round(time.Second)
+		return fmt.Sprintf("%dm%ds", d/time.Minute, d%time.Minute/time.Second)
+	default:
+		return d.Round(time.Second).String()
+	}
+}
+
+// comma formats a number with thousands separators, e.g. 1250 becomes
+// "1,250". Fractions are rounded to whole numbers.
+func comma(v interface{}) (string, error) {
+	n, err := toFloat(v)
+	if err != nil {
+		return "", err
+	}
+
+	s := strconv.FormatInt(int64(math.Round(math.Abs(n))), 10)
+	var b strings.Builder
+	if n <= -0.5 {
+		b.WriteByte('-')
+	}
+	for i, c := range s {
+		if i > 0 && (len(s)-i)%3 == 0 {
+			b.WriteByte(',')
+		}
+		b.WriteRune(c)
+	}
+	return b.String(), nil
+}
+
+// slackDate formats t as a slack date token so every viewer sees it in their
+// own time zone, e.g. slackDate "{date_short_pretty} at {time}" t. Clients
+// that can't show the token fall back to the time in UTC. A zero t is
+// formatted as an empty string.
+func slackDate(format string, t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return fmt.Sprintf("<!date^%d^%s|%s>", t.Unix(), format, t.UTC().Format("2006-01-02 15:04 UTC"))
+}
+
+// toFloat converts the numeric types that show up in templates to a float64.
+func toFloat(v interface{}) (float64, error) {
+	switch n := v.(type) {
+	case int:
+		return float64(n), nil
+	case int32:
+		return float64(n), nil
+	case int64:
+		return float64(n), nil
+	case uint:
+		return float64(n), nil
+	case uint32:
+		return float64(n), nil
+	case uint64:
+		return float64(n), nil
+	case float32:
+		return float64(n), nil
+	case float64:
+		return n, nil
+	default:
+		return 0, fmt.Errorf("Expected a number, got %T", v)
+	}
+}
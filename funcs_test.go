@@ -0,0 +1,76 @@
+package progress
+
+import (
+	"testing"
+	"time"
+)
+
+func TestHumanBytes(t *testing.T) {
+	tests := map[interface{}]string{
+		0:                 "0 B",
+		1023:              "1023 B",
+		int64(1024):       "1.0 KiB",
+		int64(1534217728): "1.4 GiB",
+		float64(5 << 40):  "5.0 TiB",
+		uint64(1 << 62):   "4.0 EiB",
+	}
+
+	for in, want := range tests {
+		got, err := humanBytes(in)
+		if err != nil || got != want {
+			t.Errorf("humanBytes(%v) = %q, %v; want %q", in, got, err, want)
+		}
+	}
+
+	if _, err := humanBytes("1024"); err == nil {
+		t.Errorf("Expected an error for a string")
+	}
+}
+
+func TestHumanDuration(t *testing.T) {
+	tests := map[time.Duration]string{
+		9*time.Second + 400*time.Millisecond:         "9s",
+		4*time.Minute + 12*time.Second:               "4m12s",
+		2*time.Hour + 3*time.Minute + 40*time.Second: "2h4m",
+	}
+
+	for in, want := range tests {
+		if got, err := humanDuration(in); err != nil || got != want {
+			t.Errorf("humanDuration(%s) = %q, %v; want %q", in, got, err, want)
+		}
+	}
+	if got, err := humanDuration("bald"); err != nil || got != "bald" {
+		t.Errorf("Expected a formatted duration to be kept, got %q, %v", got, err)
+	}
+	if _, err := humanDuration(42); err == nil {
+		t.Errorf("Expected an error for an int")
+	}
+}
+
+func TestComma(t *testing.T) {
+	tests := map[interface{}]string{
+		0:             "0",
+		999:           "999",
+		1250:          "1,250",
+		int64(-12345): "-12,345",
+		1249.6:        "1,250",
+		1234567890:    "1,234,567,890",
+	}
+
+	for in, want := range tests {
+		got, err := comma(in)
+		if err != nil || got != want {
+			t.Errorf("comma(%v) = %q, %v; want %q", in, got, err, want)
+		}
+	}
+}
+
+func TestSlackDate(t *testing.T) {
+	ts := time.Date(2024, 3, 5, 14, 32, 0, 0, time.UTC)
+	if got, want := slackDate("{time}", ts), "<!date^1709649120^{time}|2024-03-05 14:32 UTC>"; got != want {
+		t.Errorf("Expected %q, got %q", want, got)
+	}
+	if got := slackDate("{time}", time.Time{}); got != "" {
+		t.Errorf("Expected an empty string for the zero time, got %q", got)
+	}
+}
@@ -0,0 +1,144 @@
+package progress
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const githubURL = "https://api.github.com"
+
+// githubAPI returns the url of the GitHub REST API. GITHUB_API_URL, which
+// GitHub Actions sets, points it at GitHub Enterprise Server.
+func githubAPI() string {
+	if url := os.Getenv("GITHUB_API_URL"); url != "" {
+		return strings.TrimSuffix(url, "/")
+	}
+	return githubURL
+}
+
+// githubHeader returns the headers for GitHub REST API requests with token.
+func githubHeader(token string) http.Header {
+	return http.Header{
+		"Authorization":        {"Bearer " + token},
+		"Accept":               {"application/vnd.github+json"},
+		"X-Github-Api-Version": {"2022-11-28"},
+	}
+}
+
+// githubCheckSink mirrors progress messages to a GitHub check run.
+type githubCheckSink struct {
+	url   string // Check runs url of the repository
+	token string
+	sha   string
+	name  string
+}
+
+// NewGitHubCheckSink creates a Sink that shows the progress as a check run
+// named name on the commit sha of repo ("owner/name"), so a deployment shows
+// its progress on the pull request. The check run is in progress with the
+// percent in its title and the message as its summary, and completes with a
+// success or failure conclusion. Creating check runs needs a GitHub App
+// installation token, e.g. GITHUB_TOKEN in GitHub Actions with the checks:write
+// permission.
+func NewGitHubCheckSink(token, repo, sha, name string) Sink {
+	return &githubCheckSink{
+		url:   githubAPI() + "/repos/" + repo + "/check-runs",
+		token: token,
+		sha:   sha,
+		name:  name,
+	}
+}
+
+// NewGitHubCheck creates a new progress bar that shows its progress as a
+// GitHub check run. Progress is created with DefaultOptions customized by
+// opts. Combine NewGitHubCheckSink with a slack sink using NewMultiSink to
+// post to both.
+func NewGitHubCheck(token, repo, sha, name string, opts ...Option) (*Progress, error) {
+	return NewWithSink(NewGitHubCheckSink(token, repo, sha, name), opts...)
+}
+
+func (s *githubCheckSink) Post(ctx context.Context, msg *Message) (string, error) {
+	payload := s.payload(msg)
+	payload["name"] = s.name
+	payload["head_sha"] = s.sha
+	payload["started_at"] = time.Now().Add(-msg.Elapsed).UTC().Format(time.RFC3339)
+
+	var resp struct{ ID int64 }
+	if err := sendJSON(ctx, http.MethodPost, s.url, githubHeader(s.token), payload, &resp); err != nil {
+		return "", err
+	}
+
+	return strconv.FormatInt(resp.ID, 10), nil
+}
+
+func (s *githubCheckSink) Update(ctx context.Context, id string, msg *Message) error {
+	return sendJSON(ctx, http.MethodPatch, s.url+"/"+id, githubHeader(s.token), s.payload(msg), nil)
+}
+
+// payload is the state of the check run for msg.
+func (s *githubCheckSink) payload(msg *Message) map[string]interface{} {
+	title := fmt.Sprintf("%d%% complete", msg.Pct)
+	payload := map[string]interface{}{"status": "in_progress"}
+	switch {
+	case msg.Failed:
+		title = fmt.Sprintf("Failed at %d%%", msg.Pct)
+		payload["status"] = "completed"
+		payload["conclusion"] = "failure"
+	case msg.Complete:
+		title = "Completed"
+		payload["status"] = "completed"
+		payload["conclusion"] = "success"
+	}
+	if payload["status"] == "completed" {
+		payload["completed_at"] = time.Now().UTC().Format(time.RFC3339)
+	}
+
+	payload["output"] = map[string]string{"title": title, "summary": msg.Text}
+	return payload
+}
+
+// githubCommentSink posts progress messages as a comment on a GitHub pull
+// request or issue and edits it in place.
+type githubCommentSink struct {
+	url   string // Issues url of the repository
+	token string
+	num   int
+}
+
+// NewGitHubCommentSink creates a Sink that posts the progress as a comment on
+// the pull request or issue number of repo ("owner/name") and edits the
+// comment as progress is made, for teams that live in code review rather than
+// chat. token needs permission to write pull requests or issues.
+func NewGitHubCommentSink(token, repo string, number int) Sink {
+	return &githubCommentSink{
+		url:   githubAPI() + "/repos/" + repo + "/issues",
+		token: token,
+		num:   number,
+	}
+}
+
+// NewGitHubComment creates a new progress bar that posts to a comment on a
+// GitHub pull request or issue. Progress is created with DefaultOptions
+// customized by opts.
+func NewGitHubComment(token, repo string, number int, opts ...Option) (*Progress, error) {
+	return NewWithSink(NewGitHubCommentSink(token, repo, number), opts...)
+}
+
+func (s *githubCommentSink) Post(ctx context.Context, msg *Message) (string, error) {
+	url := fmt.Sprintf("%s/%d/comments", s.url, s.num)
+	var resp struct{ ID int64 }
+	if err := sendJSON(ctx, http.MethodPost, url, githubHeader(s.token), map[string]string{"body": msg.Text}, &resp); err != nil {
+		return "", err
+	}
+
+	return strconv.FormatInt(resp.ID, 10), nil
+}
+
+func (s *githubCommentSink) Update(ctx context.Context, id string, msg *Message) error {
+	return sendJSON(ctx, http.MethodPatch, s.url+"/comments/"+id, githubHeader(s.token), map[string]string{"body": msg.Text}, nil)
+}
@@ -0,0 +1,104 @@
+package progress
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestGitHubCheck(t *testing.T) {
+	var last map[string]interface{}
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if auth := r.Header.Get("Authorization"); auth != "Bearer token" {
+			t.Errorf("Expected bearer token, got %q", auth)
+		}
+		if err := json.NewDecoder(r.Body).Decode(&last); err != nil {
+			t.Errorf("Error decoding check run: %s", err)
+		}
+
+		switch {
+		case r.Method == http.MethodPost && r.URL.Path == "/repos/acme/app/check-runs":
+			if last["head_sha"] != "abc123" || last["name"] != "deploy" {
+				t.Errorf("Expected check run deploy on abc123, got %v", last)
+			}
+			w.Write([]byte(`{"id":42}`))
+		case r.Method == http.MethodPatch && r.URL.Path == "/repos/acme/app/check-runs/42":
+			w.Write([]byte(`{"id":42}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	sink := NewGitHubCheckSink("token", "acme/app", "abc123", "deploy").(*githubCheckSink)
+	sink.url = srv.URL + "/repos/acme/app/check-runs"
+
+	pbar, err := NewWithSink(sink, WithMinInterval(0))
+	if err != nil {
+		t.Fatalf("Error creating progress bar: %s", err)
+	}
+	if err := pbar.Update(40); err != nil {
+		t.Fatalf("Error updating progress bar: %s", err)
+	}
+	if last["status"] != "in_progress" || last["output"].(map[string]interface{})["title"] != "40% complete" {
+		t.Errorf("Expected the check run to be in progress at 40%%, got %v", last)
+	}
+
+	if err := pbar.Update(100); err != nil {
+		t.Fatalf("Error updating progress bar: %s", err)
+	}
+	if last["status"] != "completed" || last["conclusion"] != "success" {
+		t.Errorf("Expected the check run to succeed, got %v", last)
+	}
+}
+
+func TestGitHubCheckFailed(t *testing.T) {
+	payload := (&githubCheckSink{}).payload(&Message{Text: "boom", Pct: 30, Failed: true})
+	if payload["conclusion"] != "failure" || payload["completed_at"] == nil {
+		t.Errorf("Expected a failed check run, got %v", payload)
+	}
+}
+
+func TestGitHubComment(t *testing.T) {
+	var edits int
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var payload map[string]string
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			t.Errorf("Error decoding comment: %s", err)
+		}
+		if payload["body"] == "" {
+			t.Error("Expected the message as the body of the comment")
+		}
+
+		switch {
+		case r.Method == http.MethodPost && r.URL.Path == "/repos/acme/app/issues/12/comments":
+			w.Write([]byte(`{"id":34}`))
+		case r.Method == http.MethodPatch && r.URL.Path == "/repos/acme/app/issues/comments/34":
+			edits++
+			w.Write([]byte(`{"id":34}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	sink := NewGitHubCommentSink("token", "acme/app", 12).(*githubCommentSink)
+	sink.url = srv.URL + "/repos/acme/app/issues"
+
+	pbar, err := NewWithSink(sink, WithMinInterval(0))
+	if err != nil {
+		t.Fatalf("Error creating progress bar: %s", err)
+	}
+	for _, i := range []int{10, 50, 100} {
+		if err := pbar.Update(i); err != nil {
+			t.Fatalf("Error updating progress bar: %s", err)
+		}
+	}
+
+	if edits != 2 {
+		t.Errorf("Expected 2 edits, got %d", edits)
+	}
+}
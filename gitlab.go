@@ -0,0 +1,54 @@
+package progress
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// gitlabSink posts progress messages as a note on a GitLab merge request and
+// edits it in place.
+type gitlabSink struct {
+	url   string // Notes url of the merge request
+	token string
+}
+
+// NewGitLabCommentSink creates a Sink that posts the progress as a comment on
+// merge request mr of project and edits the comment as progress is made.
+// serverURL is the address of the GitLab server (e.g. https://gitlab.com),
+// project is its id or path (e.g. "group/app") and token is a personal,
+// project or group access token with the api scope.
+func NewGitLabCommentSink(serverURL, token, project string, mr int) Sink {
+	return &gitlabSink{
+		url: fmt.Sprintf("%s/api/v4/projects/%s/merge_requests/%d/notes",
+			strings.TrimSuffix(serverURL, "/"), url.PathEscape(project), mr),
+		token: token,
+	}
+}
+
+// NewGitLabComment creates a new progress bar that posts to a comment on a
+// GitLab merge request. Progress is created with DefaultOptions customized by
+// opts.
+func NewGitLabComment(serverURL, token, project string, mr int, opts ...Option) (*Progress, error) {
+	return NewWithSink(NewGitLabCommentSink(serverURL, token, project, mr), opts...)
+}
+
+func (s *gitlabSink) Post(ctx context.Context, msg *Message) (string, error) {
+	var resp struct{ ID int64 }
+	if err := sendJSON(ctx, http.MethodPost, s.url, s.header(), map[string]string{"body": msg.Text}, &resp); err != nil {
+		return "", err
+	}
+
+	return strconv.FormatInt(resp.ID, 10), nil
+}
+
+func (s *gitlabSink) Update(ctx context.Context, id string, msg *Message) error {
+	return sendJSON(ctx, http.MethodPut, s.url+"/"+id, s.header(), map[string]string{"body": msg.Text}, nil)
+}
+
+func (s *gitlabSink) header() http.Header {
+	return http.Header{"Authorization": {"Bearer " + s.token}}
+}
@@ -0,0 +1,57 @@
+package progress_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/sfreiberg/progress"
+)
+
+func TestGitLabComment(t *testing.T) {
+	var edits int
+	var body string
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if auth := r.Header.Get("Authorization"); auth != "Bearer token" {
+			t.Errorf("Expected bearer token, got %q", auth)
+		}
+
+		var payload map[string]string
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			t.Errorf("Error decoding gitlab payload: %s", err)
+		}
+		body = payload["body"]
+
+		switch {
+		case r.Method == http.MethodPost && r.RequestURI == "/api/v4/projects/group%2Fapp/merge_requests/7/notes":
+			w.Write([]byte(`{"id":99}`))
+		case r.Method == http.MethodPut && r.RequestURI == "/api/v4/projects/group%2Fapp/merge_requests/7/notes/99":
+			edits++
+			w.Write([]byte(`{"id":99}`))
+		default:
+			t.Errorf("Unexpected request %s %s", r.Method, r.RequestURI)
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	pbar, err := progress.NewGitLabComment(srv.URL+"/", "token", "group/app", 7, progress.WithTask("Deploy"), progress.WithMinInterval(0))
+	if err != nil {
+		t.Fatalf("Error creating progress bar: %s", err)
+	}
+	for i := 0; i <= 10; i++ {
+		if err := pbar.Update(i); err != nil {
+			t.Fatalf("Error updating progress bar: %s", err)
+		}
+	}
+
+	if edits != 9 {
+		t.Errorf("Expected 9 edits, got %d", edits)
+	}
+	if !strings.HasPrefix(body, "Deploy") || !strings.Contains(body, "10%") {
+		t.Errorf("Expected the rendered message as the comment, got %q", body)
+	}
+}
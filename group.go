@@ -0,0 +1,174 @@
+package progress
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Group renders several progress bars in a single message. Each bar is a
+// regular Progress created with Group.Add and updated independently. The
+// message is edited whenever one of the bars sends an update, at most once
+// per Options.MinInterval unless a bar has completed or failed.
+type Group struct {
+	opts *Options // Default options for every bar
+	sink Sink
+
+	mu       sync.Mutex  // Held while the message is sent
+	id       string      // The id of the message returned by the sink
+	msgs     []*Message  // The latest message of every bar, in the order they were added
+	lastSent time.Time   // When the message was last sent
+	flush    *time.Timer // Sends the progress that was throttled. Nil if nothing is pending.
+	flushes  int         // The number of flush timers started
+}
+
+// NewGroup creates a group of progress bars that share a single slack message.
+// opts are used for the message and as the default options of every bar.
+// Block Kit isn't supported, so Blocks is ignored and the text of every bar
+// is sent instead. An error is returned if the options aren't valid, see
+// Options.Validate.
+func NewGroup(token, channel string, opts ...Option) (*Group, error) {
+	o := buildOptions(opts)
+	sinkOpts := o.clone()
+	sinkOpts.Blocks = false
+	return NewGroupWithSink(NewSlackSink(token, channel, sinkOpts), o)
+}
+
+// NewGroupWithSink is like NewGroup but sends the message to sink.
+func NewGroupWithSink(sink Sink, opts ...Option) (*Group, error) {
+	o := buildOptions(opts)
+	if err := o.Validate(); err != nil {
+		return nil, err
+	}
+	return &Group{opts: o, sink: sink}, nil
+}
+
+// Add creates a progress bar for task in the group. The bar uses a copy of
+// the group's options customized by opts, with its own EWMAEstimator if the
+// group has one. It's shown below the bars added before it. Updates are
+// throttled by the group so the bar itself doesn't skip any.
+func (g *Group) Add(task string, opts ...Option) (*Progress, error) {
+	o := g.opts.clone()
+
+	g.mu.Lock()
+	member := &groupSink{group: g, index: len(g.msgs)}
+	g.msgs = append(g.msgs, nil)
+	g.mu.Unlock()
+
+	opts = append([]Option{o, WithTask(task)}, opts...)
+	return NewWithSink(member, append(opts, WithMinInterval(0))...)
+}
+
+// send renders the latest message of every bar and posts or updates the
+// group's message. Updates that arrive less than MinInterval after the last
+// one are sent once the interval has passed unless msg is final. g.mu must be
+// held.
+func (g *Group) send(ctx context.Context, index int, msg *Message) error {
+	g.msgs[index] = msg
+
+	final := msg.Complete || msg.Failed
+	if wait := g.opts.MinInterval - time.Since(g.lastSent); !final && wait > 0 {
+		if g.flush == nil {
+			g.flushes++
+			flush := g.flushes
+			g.flush = time.AfterFunc(wait, func() { g.flushPending(flush) })
+		}
+		return nil // The timer sends this progress
+	}
+	return g.post(ctx)
+}
+
+// flushPending sends the progress that was throttled, unless an update has
+// already sent it since timer number flush was started. Errors are passed to
+// OnError.
+func (g *Group) flushPending(flush int) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if g.flush == nil || g.flushes != flush {
+		return
+	}
+	if err := g.post(context.Background()); err != nil && g.opts.OnError != nil {
+		g.opts.OnError(err)
+	}
+}
+
+// post posts or updates the group's message with the latest message of every
+// bar. g.mu must be held.
+func (g *Group) post(ctx context.Context) error {
+	if g.flush != nil {
+		g.flush.Stop()
+		g.flush = nil
+	}
+
+	combined := g.combine()
+	if g.id == "" {
+		id, err := g.sink.Post(ctx, combined)
+		if err != nil {
+			return err
+		}
+		g.id = id
+	} else if err := g.sink.Update(ctx, g.id, combined); err != nil {
+		return err
+	}
+
+	g.lastSent = time.Now()
+	return nil
+}
+
+// combine creates a message with the text of every bar that has sent a
+// message. It's complete once every bar has completed and failed if any of
+// them has failed. g.mu must be held.
+func (g *Group) combine() *Message {
+	combined := &Message{Task: g.opts.Task, Complete: true}
+	texts := make([]string, 0, len(g.msgs))
+	var pct int
+
+	for _, msg := range g.msgs {
+		if msg == nil {
+			combined.Complete = false
+			continue
+		}
+
+		texts = append(texts, msg.Text)
+		pct += msg.Pct
+		combined.Pos += msg.Pos
+		combined.Total += msg.Total
+		combined.Complete = combined.Complete && msg.Complete
+		if msg.Failed && !combined.Failed {
+			combined.Failed = true
+			combined.Err = msg.Err
+		}
+		if msg.Elapsed > combined.Elapsed {
+			combined.Elapsed = msg.Elapsed
+		}
+	}
+
+	combined.Text = strings.Join(texts, "\n")
+	combined.Pct = pct / len(g.msgs)
+	return combined
+}
+
+// groupSink is the Sink of a progress bar in a Group.
+type groupSink struct {
+	group *Group
+	index int // The position of the bar in the group
+}
+
+func (s *groupSink) Post(ctx context.Context, msg *Message) (string, error) {
+	s.group.mu.Lock()
+	defer s.group.mu.Unlock()
+
+	if err := s.group.send(ctx, s.index, msg); err != nil {
+		return "", err
+	}
+	return s.group.id, nil
+}
+
+func (s *groupSink) Update(ctx context.Context, id string, msg *Message) error {
+	s.group.mu.Lock()
+	defer s.group.mu.Unlock()
+
+	return s.group.send(ctx, s.index, msg)
+}
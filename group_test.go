@@ -0,0 +1,114 @@
+package progress_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sfreiberg/progress"
+	"github.com/sfreiberg/progress/progresstest"
+)
+
+func TestGroup(t *testing.T) {
+	sink := &memSink{}
+	group, err := progress.NewGroupWithSink(sink, progress.WithMinInterval(0))
+	if err != nil {
+		t.Fatalf("Error creating group: %s", err)
+	}
+
+	extract, err := group.Add("extract")
+	if err != nil {
+		t.Fatalf("Error adding progress bar: %s", err)
+	}
+	load, err := group.Add("load", progress.WithFill("#"))
+	if err != nil {
+		t.Fatalf("Error adding progress bar: %s", err)
+	}
+
+	if err := extract.Update(50); err != nil {
+		t.Fatalf("Error updating progress bar: %s", err)
+	}
+	if err := load.Update(25); err != nil {
+		t.Fatalf("Error updating progress bar: %s", err)
+	}
+
+	if len(sink.posts) != 1 || len(sink.updates) != 1 {
+		t.Fatalf("Expected 1 post and 1 update, got %d and %d", len(sink.posts), len(sink.updates))
+	}
+	msg := sink.updates[0]
+	if !strings.Contains(msg.Text, "extract") || !strings.Contains(msg.Text, "load") {
+		t.Errorf("Expected both bars in the message, got %q", msg.Text)
+	}
+	if msg.Pct != 37 {
+		t.Errorf("Expected the average percent, got %d%%", msg.Pct)
+	}
+	if msg.Total != 200 {
+		t.Errorf("Expected the combined total, got %d", msg.Total)
+	}
+
+	if err := extract.Finish(); err != nil {
+		t.Fatalf("Error finishing progress bar: %s", err)
+	}
+	if last := sink.updates[len(sink.updates)-1]; last.Complete {
+		t.Errorf("Expected the group to be incomplete until every bar has finished")
+	}
+	if err := load.Finish(); err != nil {
+		t.Fatalf("Error finishing progress bar: %s", err)
+	}
+	if last := sink.updates[len(sink.updates)-1]; !last.Complete {
+		t.Errorf("Expected the group to be complete")
+	}
+}
+
+func TestGroupInvalidOptions(t *testing.T) {
+	if _, err := progress.NewGroupWithSink(&memSink{}, progress.WithWidth(0)); err == nil {
+		t.Errorf("Expected an error for an invalid width")
+	}
+}
+
+func TestGroupFlushesThrottledUpdates(t *testing.T) {
+	sink := &progresstest.Sink{}
+	group, err := progress.NewGroupWithSink(sink, progress.WithMinInterval(20*time.Millisecond))
+	if err != nil {
+		t.Fatalf("Error creating group: %s", err)
+	}
+	pbar, err := group.Add("extract")
+	if err != nil {
+		t.Fatalf("Error adding progress bar: %s", err)
+	}
+
+	if err := pbar.Update(10); err != nil {
+		t.Fatalf("Error updating progress bar: %s", err)
+	}
+	if err := pbar.Update(60); err != nil {
+		t.Fatalf("Error updating progress bar: %s", err)
+	}
+
+	deadline := time.Now().Add(time.Second)
+	for last := sink.Last(); last == nil || last.Pct != 60; last = sink.Last() {
+		if time.Now().After(deadline) {
+			t.Fatalf("Expected the throttled update to be sent, got %+v", last)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestGroupBarsHaveTheirOwnEstimator(t *testing.T) {
+	est := progress.NewEWMAEstimator(0.5)
+	group, err := progress.NewGroupWithSink(&progresstest.Sink{}, progress.WithEstimator(est), progress.WithMinInterval(0))
+	if err != nil {
+		t.Fatalf("Error creating group: %s", err)
+	}
+	extract, err := group.Add("extract")
+	if err != nil {
+		t.Fatalf("Error adding progress bar: %s", err)
+	}
+	load, err := group.Add("load")
+	if err != nil {
+		t.Fatalf("Error adding progress bar: %s", err)
+	}
+
+	if extract.Opts.Estimator == load.Opts.Estimator || extract.Opts.Estimator == progress.Estimator(est) {
+		t.Errorf("Expected every bar to get its own estimator")
+	}
+}
@@ -0,0 +1,49 @@
+package progress_test
+
+import (
+	"testing"
+
+	"github.com/sfreiberg/progress"
+	"github.com/sfreiberg/progress/progresstest"
+)
+
+func TestHistory(t *testing.T) {
+	pbar, sink := progresstest.New(t, progress.WithHistory(25))
+
+	for _, pos := range []int{10, 20, 30, 60, 70, 100} {
+		if err := pbar.Update(pos); err != nil {
+			t.Fatalf("Error updating progress bar: %s", err)
+		}
+	}
+
+	if len(sink.Updates()) != 0 {
+		t.Errorf("Expected no edits, got %d", len(sink.Updates()))
+	}
+	want := []int{10, 30, 60, 100}
+	posts := sink.Posts()
+	if len(posts) != len(want) {
+		t.Fatalf("Expected %d posts, got %d", len(want), len(posts))
+	}
+	for i, msg := range posts {
+		if msg.Pct != want[i] {
+			t.Errorf("Expected post %d at %d%%, got %d%%", i, want[i], msg.Pct)
+		}
+	}
+}
+
+func TestHistoryMultiSink(t *testing.T) {
+	a, b := &progresstest.Sink{}, &progresstest.Sink{}
+	pbar := newProgress(t, progress.NewMultiSink(a, b), progress.WithHistory(25))
+
+	for _, pos := range []int{10, 30, 60, 100} {
+		if err := pbar.Update(pos); err != nil {
+			t.Fatalf("Error updating progress bar: %s", err)
+		}
+	}
+
+	for _, sink := range []*progresstest.Sink{a, b} {
+		if len(sink.Posts()) != 4 || len(sink.Updates()) != 0 {
+			t.Errorf("Expected a post every step, got %d posts and %d updates", len(sink.Posts()), len(sink.Updates()))
+		}
+	}
+}
@@ -0,0 +1,135 @@
+package progress
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/slack-go/slack"
+)
+
+// maxHomeBars is the number of progress bars listed on the App Home tab.
+// Slack allows 100 blocks in a view.
+const maxHomeBars = 90
+
+// Home publishes the Running progress bars to the App Home tab of the app's
+// users, giving a single place in slack to see everything the bot is working
+// on. Publish the tab when a user opens it, i.e. on the app_home_opened
+// event, and call Run to keep the percentages of those users' tabs current.
+type Home struct {
+	client *slack.Client
+	mu     sync.Mutex
+	users  map[string]struct{} // Users the tab has been published to
+}
+
+// NewHome creates a Home that publishes views with a bot token. The App Home
+// tab has to be enabled in the app's settings. opts is only used for the
+// HTTPClient and may be nil.
+func NewHome(token string, opts *Options) *Home {
+	if opts == nil {
+		opts = DefaultOptions("Unknown Task")
+	}
+
+	return &Home{
+		client: newSlackClient(token, opts),
+		users:  map[string]struct{}{},
+	}
+}
+
+// Publish shows the Running progress bars on the App Home tab of the user
+// with the ID userID. Run keeps it current from then on.
+func (h *Home) Publish(ctx context.Context, userID string) error {
+	h.mu.Lock()
+	h.users[userID] = struct{}{}
+	h.mu.Unlock()
+
+	return h.publish(ctx, userID, h.View())
+}
+
+// Run publishes the App Home tab again to every user it has been published
+// to every interval until ctx is cancelled. It returns the errors of the last
+// round, if any, once ctx is cancelled. An interval that isn't positive is an
+// error.
+func (h *Home) Run(ctx context.Context, interval time.Duration) error {
+	if interval <= 0 {
+		return fmt.Errorf("Invalid refresh interval %s, it must be greater than 0", interval)
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	var err error
+	for {
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return err
+		}
+
+		h.mu.Lock()
+		users := make([]string, 0, len(h.users))
+		for user := range h.users {
+			users = append(users, user)
+		}
+		h.mu.Unlock()
+
+		view := h.View()
+		var errs []error
+		for _, user := range users {
+			if pubErr := h.publish(ctx, user, view); pubErr != nil && ctx.Err() == nil {
+				errs = append(errs, fmt.Errorf("%s: %w", user, pubErr))
+			}
+		}
+		err = errors.Join(errs...)
+	}
+}
+
+func (h *Home) publish(ctx context.Context, userID string, view slack.HomeTabViewRequest) error {
+	_, err := h.client.PublishViewContext(ctx, slack.PublishViewContextRequest{UserID: userID, View: view})
+	return slackError(err)
+}
+
+// View creates the App Home view with a section for every Running progress
+// bar showing its task, bar, percent and time remaining.
+func (h *Home) View() slack.HomeTabViewRequest {
+	text := func(s string) *slack.TextBlockObject {
+		return slack.NewTextBlockObject(slack.MarkdownType, s, false, false)
+	}
+
+	blocks := []slack.Block{
+		slack.NewHeaderBlock(slack.NewTextBlockObject(slack.PlainTextType, "In progress", false, false)),
+	}
+
+	bars := Running()
+	for i, bar := range bars {
+		if i == maxHomeBars {
+			blocks = append(blocks, slack.NewContextBlock("", text(fmt.Sprintf("And %d more", len(bars)-i))))
+			break
+		}
+
+		s := bar.Snapshot()
+		line := fmt.Sprintf("*%s*", s.Task)
+		if s.Phase != "" {
+			line += fmt.Sprintf(" (%s)", s.Phase)
+		}
+		if s.Permalink != "" {
+			line += fmt.Sprintf(" <%s|view>", s.Permalink)
+		}
+		line += fmt.Sprintf("\n`%s` %d%%", s.Bar, s.Pct)
+		switch {
+		case s.Paused:
+			line += "\n⏸ paused"
+		case s.Stalled:
+			line += "\n⚠️ stalled"
+		case s.Remaining > 0:
+			line += fmt.Sprintf("\n%v remaining", formatDuration(bar.Opts.Durations, s.Remaining))
+		}
+		blocks = append(blocks, slack.NewSectionBlock(text(line), nil, nil))
+	}
+	if len(bars) == 0 {
+		blocks = append(blocks, slack.NewSectionBlock(text("Nothing is running right now."), nil, nil))
+	}
+
+	return slack.HomeTabViewRequest{Type: slack.VTHomeTab, Blocks: slack.Blocks{BlockSet: blocks}}
+}
@@ -0,0 +1,80 @@
+package progress
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/slack-go/slack"
+)
+
+func TestHome(t *testing.T) {
+	var mu sync.Mutex
+	var views []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			UserID string          `json:"user_id"`
+			View   json.RawMessage `json:"view"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Errorf("Error decoding request: %s", err)
+		}
+		if r.URL.Path != "/views.publish" || req.UserID != "U123" {
+			t.Errorf("Unexpected request to %s for %q", r.URL.Path, req.UserID)
+		}
+
+		mu.Lock()
+		views = append(views, string(req.View))
+		mu.Unlock()
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"ok":true}`))
+	}))
+	defer srv.Close()
+
+	pbar, err := NewWithSink(NewJSONLinesSink(io.Discard), WithTask("home deploy"), WithMinInterval(0), WithRegister())
+	if err != nil {
+		t.Fatalf("Error creating progress bar: %s", err)
+	}
+	defer pbar.Close()
+	if err := pbar.Update(40); err != nil {
+		t.Fatalf("Error updating progress bar: %s", err)
+	}
+
+	home := NewHome("token", nil)
+	home.client = slack.New("token", slack.OptionAPIURL(srv.URL+"/"))
+	if err := home.Publish(context.Background(), "U123"); err != nil {
+		t.Fatalf("Error publishing: %s", err)
+	}
+	if !strings.Contains(views[0], `"type":"home"`) || !strings.Contains(views[0], "*home deploy*\\n`⬛⬛⬛⬛⬜⬜⬜⬜⬜⬜` 40%") {
+		t.Errorf("Unexpected view: %s", views[0])
+	}
+
+	// Run keeps the tab current
+	if err := pbar.Update(70); err != nil {
+		t.Fatalf("Error updating progress bar: %s", err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if err := home.Run(ctx, 10*time.Millisecond); err != nil {
+		t.Fatalf("Error running: %s", err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(views) < 2 || !strings.Contains(views[len(views)-1], "70%") {
+		t.Errorf("Expected the tab to be published again with 70%%, got %d views", len(views))
+	}
+}
+
+func TestHomeRunInvalidInterval(t *testing.T) {
+	home := NewHome("token", nil)
+	if err := home.Run(context.Background(), 0); err == nil {
+		t.Error("Expected an error running with an interval of 0")
+	}
+}
@@ -0,0 +1,75 @@
+package progress
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"text/template"
+)
+
+// httpSink posts every progress message as JSON to an arbitrary URL.
+type httpSink struct {
+	url    string
+	header http.Header
+	body   *template.Template // Renders the request body. The message is marshaled if it's nil.
+}
+
+// httpPayload is the request body sent by an httpSink without a template.
+type httpPayload struct {
+	Text string `json:"text"`
+	jsonLine
+}
+
+// NewHTTPSink creates a Sink that POSTs every message as JSON to url with the
+// headers in header, to integrate with systems that aren't supported
+// natively. If body is empty the request body has the text of the message
+// and the same fields as NewJSONLinesSink. Otherwise body is a template for
+// the request body, rendered with the *Message. It has the same functions as
+// message templates and json, which encodes a value as JSON:
+//
+//	{"title": {{json .Task}}, "percent": {{.Pct}}}
+//
+// An error is returned if body can't be parsed.
+func NewHTTPSink(url string, header http.Header, body string) (Sink, error) {
+	s := &httpSink{url: url, header: header}
+	if body != "" {
+		tmpl, err := template.New("body").Funcs(funcs).Funcs(template.FuncMap{"json": toJSON}).Parse(body)
+		if err != nil {
+			return nil, err
+		}
+		s.body = tmpl
+	}
+	return s, nil
+}
+
+func (s *httpSink) Post(ctx context.Context, msg *Message) (string, error) {
+	if err := s.send(ctx, msg); err != nil {
+		return "", err
+	}
+
+	// Every message is a new request so any non empty id will do
+	return s.url, nil
+}
+
+func (s *httpSink) Update(ctx context.Context, id string, msg *Message) error {
+	return s.send(ctx, msg)
+}
+
+func (s *httpSink) send(ctx context.Context, msg *Message) error {
+	if s.body == nil {
+		return sendJSON(ctx, http.MethodPost, s.url, s.header, httpPayload{Text: msg.Text, jsonLine: newJSONLine(msg)}, nil)
+	}
+
+	body := &bytes.Buffer{}
+	if err := s.body.Execute(body, msg); err != nil {
+		return err
+	}
+	return sendBody(ctx, http.MethodPost, s.url, s.header, body.Bytes(), nil)
+}
+
+// toJSON encodes v as JSON for use in templates.
+func toJSON(v interface{}) (string, error) {
+	b, err := json.Marshal(v)
+	return string(b), err
+}
@@ -0,0 +1,75 @@
+package progress_test
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/sfreiberg/progress"
+)
+
+func TestHTTPSink(t *testing.T) {
+	var (
+		mu     sync.Mutex
+		bodies []string
+		auth   []string
+	)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			t.Errorf("Error reading body: %s", err)
+		}
+
+		mu.Lock()
+		bodies = append(bodies, string(body))
+		auth = append(auth, r.Header.Get("Authorization"))
+		mu.Unlock()
+	}))
+	defer srv.Close()
+
+	header := http.Header{"Authorization": {"Bearer secret"}}
+	sink, err := progress.NewHTTPSink(srv.URL, header, "")
+	if err != nil {
+		t.Fatalf("Error creating sink: %s", err)
+	}
+	if err := newProgress(t, sink, progress.WithTask("import")).Update(40); err != nil {
+		t.Fatalf("Error updating progress bar: %s", err)
+	}
+
+	sink, err = progress.NewHTTPSink(srv.URL, header, `{"title": {{json .Task}}, "percent": {{.Pct}}}`)
+	if err != nil {
+		t.Fatalf("Error creating sink: %s", err)
+	}
+	if err := newProgress(t, sink, progress.WithTask(`say "hi"`)).Update(60); err != nil {
+		t.Fatalf("Error updating progress bar: %s", err)
+	}
+
+	if len(bodies) != 2 {
+		t.Fatalf("Expected 2 requests, got %d", len(bodies))
+	}
+	var payload struct {
+		Text string
+		Task string
+		Pct  int
+	}
+	if err := json.Unmarshal([]byte(bodies[0]), &payload); err != nil {
+		t.Fatalf("Error decoding %q: %s", bodies[0], err)
+	}
+	if payload.Task != "import" || payload.Pct != 40 || payload.Text == "" {
+		t.Errorf("Unexpected payload %s", bodies[0])
+	}
+	if want := `{"title": "say \"hi\"", "percent": 60}`; bodies[1] != want {
+		t.Errorf("Expected %s, got %s", want, bodies[1])
+	}
+	if auth[0] != "Bearer secret" || auth[1] != "Bearer secret" {
+		t.Errorf("Expected the headers to be sent, got %v", auth)
+	}
+
+	if _, err := progress.NewHTTPSink(srv.URL, nil, "{{.Task"); err == nil {
+		t.Error("Expected an error for an invalid template")
+	}
+}
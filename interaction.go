@@ -0,0 +1,155 @@
+package progress
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+
+	"github.com/slack-go/slack"
+)
+
+// AbortActionID is the action id of the button created by AbortButton.
+const AbortActionID = "progress_abort"
+
+// AbortButton creates a button that asks the user for confirmation and then
+// requests the running task to be aborted. Add it to Options.Buttons (or use
+// WithAbortButton) and pass the interactions slack sends to
+// Progress.HandleInteraction.
+func AbortButton() *slack.ButtonBlockElement {
+	text := func(s string) *slack.TextBlockObject {
+		return slack.NewTextBlockObject(slack.PlainTextType, s, false, false)
+	}
+
+	return slack.NewButtonBlockElement(AbortActionID, "abort", text("Abort")).
+		WithStyle(slack.StyleDanger).
+		WithConfirm(slack.NewConfirmationBlockObject(
+			text("Abort task?"),
+			text("The running task will be asked to stop."),
+			text("Abort"),
+			text("Keep running"),
+		))
+}
+
+// WithButton renders the message with Block Kit and adds a button below the
+// progress bar that calls fn when it's pressed, e.g. to pause the task or
+// reply with details. actionID must be unique within the message.
+func WithButton(actionID, label string, fn func(cb *slack.InteractionCallback)) Option {
+	return optionFunc(func(o *Options) {
+		o.Blocks = true
+		o.Buttons = append(o.Buttons, slack.NewButtonBlockElement(actionID, actionID, slack.NewTextBlockObject(slack.PlainTextType, label, false, false)))
+		if o.Actions == nil {
+			o.Actions = map[string]func(cb *slack.InteractionCallback){}
+		}
+		o.Actions[actionID] = fn
+	})
+}
+
+// WithAbortButton renders the message with Block Kit and adds an AbortButton
+// below the progress bar.
+func WithAbortButton() Option {
+	return optionFunc(func(o *Options) {
+		o.Blocks = true
+		o.Buttons = append(o.Buttons, AbortButton())
+	})
+}
+
+// Aborted returns a channel that's closed when the task has been asked to
+// abort, either by a user pressing the AbortButton or by calling Abort.
+func (p *Progress) Aborted() <-chan struct{} {
+	return p.aborted
+}
+
+// Abort asks the running task to stop by closing the channel returned by
+// Aborted. It's safe to call Abort more than once.
+func (p *Progress) Abort() {
+	p.abortOnce.Do(func() { close(p.aborted) })
+}
+
+// HandleInteraction handles a slack interaction payload. If it's the
+// AbortButton of this progress bar being pressed Abort is called and true is
+// returned. Buttons with a callback in Options.Actions, see WithButton, call
+// it instead. Interactions for other messages are ignored and false is
+// returned so the same callback can be offered to several progress bars.
+func (p *Progress) HandleInteraction(cb *slack.InteractionCallback) bool {
+	if cb.Type != slack.InteractionTypeBlockActions {
+		return false
+	}
+
+	p.mu.Lock()
+	id := p.id
+	p.mu.Unlock()
+
+	if id == "" || cb.Container.MessageTs != id {
+		return false
+	}
+
+	for _, action := range cb.ActionCallback.BlockActions {
+		if action.ActionID == AbortActionID {
+			p.Abort()
+			return true
+		}
+		if fn := p.Opts.Actions[action.ActionID]; fn != nil {
+			fn(cb)
+			return true
+		}
+	}
+
+	return false
+}
+
+// Dispatch passes a slack interaction payload to HandleInteraction of every
+// Running progress bar until one of them handles it and returns whether one
+// did. Use it to route the button clicks received by InteractionHandler or
+// through Socket Mode without keeping track of the progress bars yourself.
+func Dispatch(cb *slack.InteractionCallback) bool {
+	for _, bar := range Running() {
+		if bar.HandleInteraction(cb) {
+			return true
+		}
+	}
+	return false
+}
+
+// InteractionHandler creates an http.Handler for slack's interactivity
+// request URL. Requests are verified with the app's signing secret and every
+// interaction payload is passed to handle, e.g. to Progress.HandleInteraction.
+func InteractionHandler(signingSecret string, handle func(cb *slack.InteractionCallback)) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !verifyRequest(w, r, signingSecret) {
+			return
+		}
+
+		cb, err := slack.InteractionCallbackParse(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		handle(&cb)
+		w.WriteHeader(http.StatusOK)
+	})
+}
+
+// verifyRequest verifies that r was sent by slack using the app's signing
+// secret and replaces the body so it can be read again. If it wasn't an error
+// is written to w and false is returned.
+func verifyRequest(w http.ResponseWriter, r *http.Request, signingSecret string) bool {
+	verifier, err := slack.NewSecretsVerifier(r.Header, signingSecret)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return false
+	}
+
+	body, err := io.ReadAll(io.TeeReader(r.Body, &verifier))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return false
+	}
+	if err := verifier.Ensure(); err != nil {
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return false
+	}
+
+	r.Body = io.NopCloser(bytes.NewReader(body))
+	return true
+}
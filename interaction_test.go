@@ -0,0 +1,87 @@
+package progress_test
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sfreiberg/progress"
+	"github.com/slack-go/slack"
+)
+
+func TestAbortInteraction(t *testing.T) {
+	const secret = "signing-secret"
+
+	pbar := newProgress(t, &memSink{}, progress.WithAbortButton())
+	if err := pbar.Update(10); err != nil {
+		t.Fatalf("Error updating progress bar: %s", err)
+	}
+
+	handler := progress.InteractionHandler(secret, func(cb *slack.InteractionCallback) {
+		pbar.HandleInteraction(cb)
+	})
+
+	payload := fmt.Sprintf(`{"type":"block_actions","container":{"message_ts":"1"},"actions":[{"block_id":"progress_actions","action_id":%q}]}`, progress.AbortActionID)
+	body := url.Values{"payload": {payload}}.Encode()
+
+	// An unsigned request must be rejected
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body)))
+	if w.Code != http.StatusUnauthorized {
+		t.Fatalf("Expected unsigned request to be rejected, got %d", w.Code)
+	}
+
+	ts := strconv.FormatInt(time.Now().Unix(), 10)
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte("v0:" + ts + ":" + body))
+
+	r := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+	r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	r.Header.Set("X-Slack-Request-Timestamp", ts)
+	r.Header.Set("X-Slack-Signature", "v0="+hex.EncodeToString(mac.Sum(nil)))
+
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, r)
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected %d, got %d: %s", http.StatusOK, w.Code, w.Body)
+	}
+
+	select {
+	case <-pbar.Aborted():
+	default:
+		t.Fatalf("Expected progress bar to be aborted")
+	}
+}
+
+func TestDispatch(t *testing.T) {
+	var pressed bool
+	pbar := newProgress(t, &memSink{}, progress.WithRegister(), progress.WithButton("dispatch_pause", "Pause", func(cb *slack.InteractionCallback) {
+		pressed = true
+	}))
+	defer pbar.Close()
+	if err := pbar.Update(10); err != nil {
+		t.Fatalf("Error updating progress bar: %s", err)
+	}
+
+	cb := &slack.InteractionCallback{
+		Type:           slack.InteractionTypeBlockActions,
+		Container:      slack.Container{MessageTs: pbar.MessageTS()},
+		ActionCallback: slack.ActionCallbacks{BlockActions: []*slack.BlockAction{{ActionID: "dispatch_pause"}}},
+	}
+	if !progress.Dispatch(cb) || !pressed {
+		t.Errorf("Expected the button's callback to be called")
+	}
+
+	cb.ActionCallback.BlockActions[0].ActionID = "unknown"
+	if progress.Dispatch(cb) {
+		t.Errorf("Expected an unknown action not to be handled")
+	}
+}
@@ -0,0 +1,90 @@
+package progress
+
+import (
+	"context"
+	"encoding/base64"
+	"net/http"
+	"strings"
+)
+
+// jira has what's shared by the Jira sinks.
+type jira struct {
+	url    string // Url of the issue in the v2 REST API
+	header http.Header
+}
+
+// newJira authenticates with the email address user and an API token on Jira
+// Cloud, or with a personal access token on Jira Server and Data Center if
+// user is empty.
+func newJira(serverURL, user, token, issue string) jira {
+	auth := "Bearer " + token
+	if user != "" {
+		auth = "Basic " + base64.StdEncoding.EncodeToString([]byte(user+":"+token))
+	}
+
+	return jira{
+		url:    strings.TrimSuffix(serverURL, "/") + "/rest/api/2/issue/" + issue,
+		header: http.Header{"Authorization": {auth}},
+	}
+}
+
+// jiraCommentSink posts progress messages as a comment on a Jira issue and
+// edits it in place.
+type jiraCommentSink struct {
+	jira
+}
+
+// NewJiraSink creates a Sink that posts the progress as a comment on a Jira
+// issue, e.g. "OPS-123", and edits the comment as the task advances, so long
+// running tickets show their live status. serverURL is the address of the
+// Jira site (e.g. https://example.atlassian.net). On Jira Cloud user is the
+// email address the API token belongs to. On Jira Server and Data Center
+// leave user empty and pass a personal access token.
+func NewJiraSink(serverURL, user, token, issue string) Sink {
+	return &jiraCommentSink{newJira(serverURL, user, token, issue)}
+}
+
+// NewJira creates a new progress bar that posts to a comment on a Jira issue.
+// Progress is created with DefaultOptions customized by opts.
+func NewJira(serverURL, user, token, issue string, opts ...Option) (*Progress, error) {
+	return NewWithSink(NewJiraSink(serverURL, user, token, issue), opts...)
+}
+
+func (s *jiraCommentSink) Post(ctx context.Context, msg *Message) (string, error) {
+	var resp struct{ ID string }
+	if err := sendJSON(ctx, http.MethodPost, s.url+"/comment", s.header, map[string]string{"body": msg.Text}, &resp); err != nil {
+		return "", err
+	}
+
+	return resp.ID, nil
+}
+
+func (s *jiraCommentSink) Update(ctx context.Context, id string, msg *Message) error {
+	return sendJSON(ctx, http.MethodPut, s.url+"/comment/"+id, s.header, map[string]string{"body": msg.Text}, nil)
+}
+
+// jiraFieldSink sets a number field of a Jira issue to the percent complete.
+type jiraFieldSink struct {
+	jira
+	field string
+}
+
+// NewJiraFieldSink creates a Sink that sets the custom number field of a Jira
+// issue, e.g. "customfield_10042", to the percent complete as the task
+// advances. The field has to be on the issue's edit screen. See NewJiraSink
+// for serverURL, user and token.
+func NewJiraFieldSink(serverURL, user, token, issue, field string) Sink {
+	return &jiraFieldSink{jira: newJira(serverURL, user, token, issue), field: field}
+}
+
+func (s *jiraFieldSink) Post(ctx context.Context, msg *Message) (string, error) {
+	// The issue is updated in place so any non empty id will do
+	return s.url, s.Update(ctx, s.url, msg)
+}
+
+func (s *jiraFieldSink) Update(ctx context.Context, id string, msg *Message) error {
+	payload := map[string]interface{}{
+		"fields": map[string]interface{}{s.field: msg.Pct},
+	}
+	return sendJSON(ctx, http.MethodPut, s.url, s.header, payload, nil)
+}
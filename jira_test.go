@@ -0,0 +1,89 @@
+package progress_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/sfreiberg/progress"
+)
+
+func TestJira(t *testing.T) {
+	var edits int
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if user, token, ok := r.BasicAuth(); !ok || user != "me@example.com" || token != "token" {
+			t.Errorf("Expected basic auth, got %q", r.Header.Get("Authorization"))
+		}
+
+		var payload map[string]string
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			t.Errorf("Error decoding jira payload: %s", err)
+		}
+		if payload["body"] == "" {
+			t.Error("Expected the message as the body of the comment")
+		}
+
+		switch {
+		case r.Method == http.MethodPost && r.URL.Path == "/rest/api/2/issue/OPS-123/comment":
+			w.Write([]byte(`{"id":"10001"}`))
+		case r.Method == http.MethodPut && r.URL.Path == "/rest/api/2/issue/OPS-123/comment/10001":
+			edits++
+			w.Write([]byte(`{"id":"10001"}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	pbar, err := progress.NewJira(srv.URL, "me@example.com", "token", "OPS-123", progress.WithMinInterval(0))
+	if err != nil {
+		t.Fatalf("Error creating progress bar: %s", err)
+	}
+	for i := 0; i <= 10; i++ {
+		if err := pbar.Update(i); err != nil {
+			t.Fatalf("Error updating progress bar: %s", err)
+		}
+	}
+
+	if edits != 9 {
+		t.Errorf("Expected 9 edits, got %d", edits)
+	}
+}
+
+func TestJiraField(t *testing.T) {
+	var pcts []float64
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if auth := r.Header.Get("Authorization"); auth != "Bearer pat" {
+			t.Errorf("Expected bearer token, got %q", auth)
+		}
+		if r.Method != http.MethodPut || r.URL.Path != "/rest/api/2/issue/OPS-123" {
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+		}
+
+		var payload struct{ Fields map[string]float64 }
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			t.Errorf("Error decoding jira payload: %s", err)
+		}
+		pcts = append(pcts, payload.Fields["customfield_10042"])
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+
+	sink := progress.NewJiraFieldSink(srv.URL+"/", "", "pat", "OPS-123", "customfield_10042")
+	pbar, err := progress.NewWithSink(sink, progress.WithMinInterval(0))
+	if err != nil {
+		t.Fatalf("Error creating progress bar: %s", err)
+	}
+	for _, i := range []int{25, 100} {
+		if err := pbar.Update(i); err != nil {
+			t.Fatalf("Error updating progress bar: %s", err)
+		}
+	}
+
+	if len(pcts) != 2 || pcts[0] != 25 || pcts[1] != 100 {
+		t.Errorf("Expected the field to be set to 25 and 100, got %v", pcts)
+	}
+}
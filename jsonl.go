@@ -0,0 +1,77 @@
+package progress
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"time"
+)
+
+// jsonLinesSink writes every progress message to a writer as a line of JSON.
+type jsonLinesSink struct {
+	enc *json.Encoder
+}
+
+// jsonLine is a progress message written by the JSON lines sink.
+type jsonLine struct {
+	TS        time.Time  `json:"ts"`
+	Task      string     `json:"task"`
+	Pos       int64      `json:"pos"`
+	Total     int64      `json:"total"`
+	Pct       int        `json:"pct"`
+	Rate      float64    `json:"rate"`
+	Elapsed   float64    `json:"elapsed"`             // Seconds
+	Remaining float64    `json:"remaining,omitempty"` // Seconds
+	ETA       *time.Time `json:"eta,omitempty"`
+	Complete  bool       `json:"complete,omitempty"`
+	Failed    bool       `json:"failed,omitempty"`
+	Error     string     `json:"error,omitempty"`
+}
+
+// NewJSONLinesSink creates a Sink that writes every message to w as a JSON
+// object on its own line, with the position, percent, rate and estimated
+// completion time, so dashboards and log processors can follow the progress.
+// Combine it with a slack sink using NewMultiSink to send both.
+func NewJSONLinesSink(w io.Writer) Sink {
+	return &jsonLinesSink{enc: json.NewEncoder(w)}
+}
+
+func (s *jsonLinesSink) Post(ctx context.Context, msg *Message) (string, error) {
+	if err := s.write(msg); err != nil {
+		return "", err
+	}
+
+	// Every message is a new line so any non empty id will do
+	return "jsonl", nil
+}
+
+func (s *jsonLinesSink) Update(ctx context.Context, id string, msg *Message) error {
+	return s.write(msg)
+}
+
+func (s *jsonLinesSink) write(msg *Message) error {
+	return s.enc.Encode(newJSONLine(msg))
+}
+
+// newJSONLine creates the JSON object written for msg.
+func newJSONLine(msg *Message) jsonLine {
+	line := jsonLine{
+		TS:        time.Now(),
+		Task:      msg.Task,
+		Pos:       msg.Pos,
+		Total:     msg.Total,
+		Pct:       msg.Pct,
+		Rate:      msg.Rate,
+		Elapsed:   msg.Elapsed.Seconds(),
+		Remaining: msg.Remaining.Seconds(),
+		Complete:  msg.Complete,
+		Failed:    msg.Failed,
+	}
+	if !msg.ETA.IsZero() {
+		line.ETA = &msg.ETA
+	}
+	if msg.Err != nil {
+		line.Error = msg.Err.Error()
+	}
+	return line
+}
@@ -0,0 +1,43 @@
+package progress_test
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/sfreiberg/progress"
+)
+
+func TestJSONLinesSink(t *testing.T) {
+	var out strings.Builder
+	pbar := newProgress(t, progress.NewJSONLinesSink(&out), progress.WithTask("import"))
+
+	if err := pbar.Update(40); err != nil {
+		t.Fatalf("Error updating progress bar: %s", err)
+	}
+	if err := pbar.Fail(errors.New("disk full")); err != nil {
+		t.Fatalf("Error failing progress bar: %s", err)
+	}
+
+	var lines []map[string]interface{}
+	scanner := bufio.NewScanner(strings.NewReader(out.String()))
+	for scanner.Scan() {
+		var line map[string]interface{}
+		if err := json.Unmarshal(scanner.Bytes(), &line); err != nil {
+			t.Fatalf("Error decoding %q: %s", scanner.Text(), err)
+		}
+		lines = append(lines, line)
+	}
+
+	if len(lines) != 2 {
+		t.Fatalf("Expected 2 lines, got %d", len(lines))
+	}
+	if lines[0]["task"] != "import" || lines[0]["pos"] != 40.0 || lines[0]["pct"] != 40.0 || lines[0]["ts"] == nil {
+		t.Errorf("Unexpected first line: %v", lines[0])
+	}
+	if lines[1]["failed"] != true || lines[1]["error"] != "disk full" {
+		t.Errorf("Unexpected last line: %v", lines[1])
+	}
+}
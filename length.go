@@ -0,0 +1,38 @@
+package progress
+
+import "unicode/utf8"
+
+// DefaultMaxLength is the longest message, in characters, that's sent unless
+// Options.MaxLength says otherwise. Slack shows about this much of a message
+// before cutting it off and rejects messages over 40,000 characters.
+const DefaultMaxLength = 4000
+
+// renderFit renders tmpl for msg into msg.Text. If the text is longer than
+// Options.MaxLength the oldest log lines are dropped one at a time, then the
+// status, until it fits. A *TooLongError is returned if it still doesn't.
+func (p *Progress) renderFit(msg *Message, tmpl string) error {
+	max := p.Opts.MaxLength
+	if max <= 0 {
+		max = DefaultMaxLength
+	}
+
+	for {
+		text, err := p.render(msg, tmpl)
+		if err != nil {
+			return err
+		}
+
+		n := utf8.RuneCountInString(text)
+		switch {
+		case n <= max:
+			msg.Text = text
+			return nil
+		case len(msg.Log) > 0:
+			msg.Log = msg.Log[1:]
+		case msg.Status != "":
+			msg.Status = ""
+		default:
+			return &TooLongError{Length: n, Max: max}
+		}
+	}
+}
@@ -0,0 +1,37 @@
+package progress
+
+import (
+	"context"
+	"strings"
+)
+
+// DefaultLogLines is the number of lines kept by Progress.Log unless
+// Options.LogLines says otherwise.
+const DefaultLogLines = 5
+
+// Log adds line to the log shown in a code block beneath the bar. Only the
+// last Options.LogLines lines are kept. The log is available in templates as
+// .Log and adding a line re-renders the message, subject to
+// Options.MinInterval.
+func (p *Progress) Log(line string) error {
+	return p.LogContext(context.Background(), line)
+}
+
+// LogContext is like Log but gives up on sending the message when ctx is
+// cancelled or times out.
+func (p *Progress) LogContext(ctx context.Context, line string) error {
+	line = strings.TrimRight(line, "\n")
+
+	return p.rerender(ctx, func() bool {
+		n := p.Opts.LogLines
+		if n <= 0 {
+			n = DefaultLogLines
+		}
+
+		p.logTail = append(p.logTail, line)
+		if len(p.logTail) > n {
+			p.logTail = append([]string(nil), p.logTail[len(p.logTail)-n:]...)
+		}
+		return true
+	})
+}
@@ -0,0 +1,60 @@
+package progress_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/sfreiberg/progress"
+	"github.com/sfreiberg/progress/progresstest"
+)
+
+func TestLog(t *testing.T) {
+	sink := &memSink{}
+	pbar := newProgress(t, sink, progress.WithLogLines(2))
+
+	for _, line := range []string{"copying a.txt\n", "copying b.txt", "warning: c.txt is empty"} {
+		if err := pbar.Log(line); err != nil {
+			t.Fatalf("Error logging: %s", err)
+		}
+	}
+
+	last := sink.updates[len(sink.updates)-1]
+	if len(last.Log) != 2 || last.Log[0] != "copying b.txt" {
+		t.Fatalf("Expected the last 2 lines, got %q", last.Log)
+	}
+	if !strings.HasSuffix(last.Text, "```\ncopying b.txt\nwarning: c.txt is empty\n```") {
+		t.Errorf("Expected the log in a code block, got %q", last.Text)
+	}
+}
+
+func TestMaxLength(t *testing.T) {
+	pbar, sink := progresstest.New(t, progress.WithTask("import"), progress.WithMaxLength(100))
+
+	if err := pbar.SetStatus("reading users.csv"); err != nil {
+		t.Fatalf("Error setting status: %s", err)
+	}
+	for _, line := range []string{"first line of the log", "second line of the log"} {
+		if err := pbar.Log(line); err != nil {
+			t.Fatalf("Error logging: %s", err)
+		}
+	}
+
+	last := sink.Last()
+	if strings.Contains(last.Text, "first line") || !strings.Contains(last.Text, "second line") || !strings.Contains(last.Text, "users.csv") {
+		t.Errorf("Expected the oldest log line to be dropped, got %q", last.Text)
+	}
+
+	if err := pbar.SetStatus(strings.Repeat("x", 100)); err != nil {
+		t.Fatalf("Error setting status: %s", err)
+	}
+	if last := sink.Last(); last.Status != "" || strings.Contains(last.Text, "x") {
+		t.Errorf("Expected the status to be dropped, got %q", last.Text)
+	}
+
+	pbar, _ = progresstest.New(t, progress.WithTask(strings.Repeat("x", 100)), progress.WithMaxLength(100))
+	var tooLong *progress.TooLongError
+	if err := pbar.Update(50); !errors.As(err, &tooLong) || tooLong.Max != 100 {
+		t.Errorf("Expected a TooLongError, got %v", err)
+	}
+}
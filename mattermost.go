@@ -0,0 +1,55 @@
+package progress
+
+import (
+	"context"
+	"net/http"
+	"strings"
+)
+
+// mattermostSink posts progress messages with the Mattermost REST API and
+// edits them in place.
+type mattermostSink struct {
+	url       string // Base url of the v4 api
+	token     string
+	channelID string
+}
+
+// NewMattermostSink creates a Sink that posts to a Mattermost channel.
+// serverURL is the address of the Mattermost server (e.g.
+// https://chat.example.com) and token is a personal access or bot token.
+func NewMattermostSink(serverURL, token, channelID string) Sink {
+	return &mattermostSink{
+		url:       strings.TrimSuffix(serverURL, "/") + "/api/v4",
+		token:     token,
+		channelID: channelID,
+	}
+}
+
+// NewMattermost creates a new progress bar that posts to a Mattermost channel.
+// Progress is created with DefaultOptions customized by opts.
+func NewMattermost(serverURL, token, channelID string, opts ...Option) (*Progress, error) {
+	return NewWithSink(NewMattermostSink(serverURL, token, channelID), opts...)
+}
+
+func (s *mattermostSink) Post(ctx context.Context, msg *Message) (string, error) {
+	payload := map[string]string{
+		"channel_id": s.channelID,
+		"message":    msg.Text,
+	}
+
+	var resp struct{ ID string }
+	if err := sendJSON(ctx, http.MethodPost, s.url+"/posts", s.header(), payload, &resp); err != nil {
+		return "", err
+	}
+
+	return resp.ID, nil
+}
+
+func (s *mattermostSink) Update(ctx context.Context, id string, msg *Message) error {
+	payload := map[string]string{"message": msg.Text}
+	return sendJSON(ctx, http.MethodPut, s.url+"/posts/"+id+"/patch", s.header(), payload, nil)
+}
+
+func (s *mattermostSink) header() http.Header {
+	return http.Header{"Authorization": {"Bearer " + s.token}}
+}
@@ -0,0 +1,53 @@
+package progress_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/sfreiberg/progress"
+)
+
+func TestMattermost(t *testing.T) {
+	var patches int
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if auth := r.Header.Get("Authorization"); auth != "Bearer token" {
+			t.Errorf("Expected bearer token, got %q", auth)
+		}
+
+		var payload map[string]string
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			t.Errorf("Error decoding mattermost payload: %s", err)
+		}
+
+		switch {
+		case r.Method == http.MethodPost && r.URL.Path == "/api/v4/posts":
+			if payload["channel_id"] != "town-square" {
+				t.Errorf("Expected channel town-square, got %q", payload["channel_id"])
+			}
+			w.Write([]byte(`{"id":"abc"}`))
+		case r.Method == http.MethodPut && r.URL.Path == "/api/v4/posts/abc/patch":
+			patches++
+			w.Write([]byte(`{"id":"abc"}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	pbar, err := progress.NewMattermost(srv.URL+"/", "token", "town-square", progress.WithMinInterval(0))
+	if err != nil {
+		t.Fatalf("Error creating progress bar: %s", err)
+	}
+	for i := 0; i <= 10; i++ {
+		if err := pbar.Update(i); err != nil {
+			t.Fatalf("Error updating progress bar: %s", err)
+		}
+	}
+
+	if patches != 9 {
+		t.Errorf("Expected 9 patches, got %d", patches)
+	}
+}
@@ -0,0 +1,26 @@
+package progress
+
+import "github.com/slack-go/slack"
+
+// MetadataEventType is the event type of the message metadata attached when
+// Options.Metadata is set.
+const MetadataEventType = "progress_update"
+
+// metadata creates the message metadata for msg. The payload has the task,
+// pos, total, pct, complete and failed and, while there's an estimate, the
+// remaining seconds and the eta as a unix timestamp.
+func metadata(msg *Message) slack.SlackMetadata {
+	payload := map[string]interface{}{
+		"task":     msg.Task,
+		"pos":      msg.Pos,
+		"total":    msg.Total,
+		"pct":      msg.Pct,
+		"complete": msg.Complete,
+		"failed":   msg.Failed,
+	}
+	if !msg.ETA.IsZero() && !msg.Complete && !msg.Failed {
+		payload["remaining"] = int64(msg.Remaining.Seconds())
+		payload["eta"] = msg.ETA.Unix()
+	}
+	return slack.SlackMetadata{EventType: MetadataEventType, EventPayload: payload}
+}
@@ -0,0 +1,50 @@
+package progress
+
+import (
+	"encoding/json"
+	"net/url"
+	"testing"
+
+	"github.com/slack-go/slack"
+)
+
+func TestMetadata(t *testing.T) {
+	var metadata []string
+
+	opts := DefaultOptions("deploy")
+	opts.Metadata = true
+	opts.MinInterval = 0
+	sink, done := newTestSlackSink(t, opts, func(method string, form url.Values) string {
+		metadata = append(metadata, form.Get("metadata"))
+		return `{"ok":true,"channel":"C123","ts":"1.2"}`
+	})
+	defer done()
+
+	pbar, err := NewWithSink(sink, opts)
+	if err != nil {
+		t.Fatalf("Error creating progress bar: %s", err)
+	}
+	for _, pos := range []int{40, 100} {
+		if err := pbar.Update(pos); err != nil {
+			t.Fatalf("Error updating progress bar: %s", err)
+		}
+	}
+
+	if len(metadata) != 2 {
+		t.Fatalf("Expected a post and an update, got %d requests", len(metadata))
+	}
+	var first, last slack.SlackMetadata
+	if err := json.Unmarshal([]byte(metadata[0]), &first); err != nil {
+		t.Fatalf("Error decoding %q: %s", metadata[0], err)
+	}
+	if err := json.Unmarshal([]byte(metadata[1]), &last); err != nil {
+		t.Fatalf("Error decoding %q: %s", metadata[1], err)
+	}
+
+	if first.EventType != MetadataEventType || first.EventPayload["task"] != "deploy" || first.EventPayload["pos"] != 40.0 || first.EventPayload["total"] != 100.0 {
+		t.Errorf("Unexpected metadata of the post: %+v", first)
+	}
+	if last.EventPayload["complete"] != true || last.EventPayload["eta"] != nil {
+		t.Errorf("Unexpected metadata of the final update: %+v", last)
+	}
+}
@@ -0,0 +1,127 @@
+package progress
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// multiSink sends every message to several sinks, e.g. a slack sink per
+// channel. The id of the message in every sink is kept here, so a sink that
+// failed to post is posted to again with the next message while the others
+// are updated.
+type multiSink struct {
+	sinks []Sink
+	names []string // Name of every sink used in errors, e.g. the channel. Optional.
+	ids   []string // Message id per sink. Empty until the message has been posted.
+}
+
+// NewMultiSink creates a Sink that sends every message to all of sinks. The
+// errors of the sinks that fail are joined together.
+func NewMultiSink(sinks ...Sink) Sink {
+	return &multiSink{
+		sinks: sinks,
+		ids:   make([]string, len(sinks)),
+	}
+}
+
+// NewMulti creates a new progress bar that's posted to every channel in
+// channels and kept up to date in all of them. Progress is created with
+// DefaultOptions customized by opts.
+func NewMulti(token string, channels []string, opts ...Option) (*Progress, error) {
+	o := buildOptions(opts)
+
+	m := &multiSink{names: channels, ids: make([]string, len(channels))}
+	for _, channel := range channels {
+		m.sinks = append(m.sinks, NewSlackSink(token, channel, o))
+	}
+	return NewWithSink(m, o)
+}
+
+// Post posts msg as a new message to every sink, even the ones that have
+// posted before, e.g. after Progress.Reset or in history mode. The id is
+// returned even if some sinks failed, so they're posted to again by Update.
+func (m *multiSink) Post(ctx context.Context, msg *Message) (string, error) {
+	clear(m.ids)
+	err := m.send(ctx, msg)
+	return m.id(), err
+}
+
+// Update updates msg in the sinks that have posted it and posts it to the
+// others.
+func (m *multiSink) Update(ctx context.Context, id string, msg *Message) error {
+	return m.send(ctx, msg)
+}
+
+// Notify sends the notification through every sink that's a Notifier and has
+// posted the message.
+func (m *multiSink) Notify(ctx context.Context, id, text string, mentions []string) error {
+	var errs []error
+	for i, sink := range m.sinks {
+		if n, ok := sink.(Notifier); ok && m.ids[i] != "" {
+			errs = append(errs, m.err(i, n.Notify(ctx, m.ids[i], text, mentions)))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Delete deletes the message in every sink that's a Deleter.
+func (m *multiSink) Delete(ctx context.Context, id string) error {
+	var errs []error
+	for i, sink := range m.sinks {
+		if d, ok := sink.(Deleter); ok && m.ids[i] != "" {
+			errs = append(errs, m.err(i, d.Delete(ctx, m.ids[i])))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// send posts msg to the sinks that haven't posted it yet and updates it in the
+// others. A sink whose message can't be edited anymore posts a new one, so
+// ErrCantEdit from one sink doesn't make the others post again. ErrSkipped is
+// only returned if every sink skipped msg.
+func (m *multiSink) send(ctx context.Context, msg *Message) error {
+	var errs []error
+	skipped := 0
+	for i, sink := range m.sinks {
+		if m.ids[i] != "" {
+			err := sink.Update(ctx, m.ids[i], msg)
+			if errors.Is(err, ErrSkipped) {
+				skipped++
+				continue
+			}
+			if !errors.Is(err, ErrCantEdit) {
+				errs = append(errs, m.err(i, err))
+				continue
+			}
+			m.ids[i] = ""
+		}
+
+		id, err := sink.Post(ctx, msg)
+		if err == nil {
+			m.ids[i] = id
+		}
+		errs = append(errs, m.err(i, err))
+	}
+	if skipped > 0 && skipped == len(m.sinks) {
+		return ErrSkipped
+	}
+	return errors.Join(errs...)
+}
+
+// id is the id of the message in every sink, separated by commas.
+func (m *multiSink) id() string {
+	if strings.Join(m.ids, "") == "" {
+		return ""
+	}
+	return strings.Join(m.ids, ",")
+}
+
+// err adds the name of sink i to err, if there is one.
+func (m *multiSink) err(i int, err error) error {
+	if err == nil || m.names == nil {
+		return err
+	}
+	return fmt.Errorf("%s: %w", m.names[i], err)
+}
@@ -0,0 +1,76 @@
+package progress_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/sfreiberg/progress"
+	"github.com/sfreiberg/progress/progresstest"
+)
+
+func TestMultiSink(t *testing.T) {
+	errDown := errors.New("down")
+	a, b := &progresstest.Sink{}, &progresstest.Sink{PostErr: errDown}
+	pbar := newProgress(t, progress.NewMultiSink(a, b), progress.WithTask("release"))
+
+	if err := pbar.Update(50); !errors.Is(err, errDown) {
+		t.Fatalf("Expected the error of the failing sink, got %v", err)
+	}
+
+	b.PostErr = nil
+	if err := pbar.Update(100); err != nil {
+		t.Fatalf("Error updating progress bar: %s", err)
+	}
+
+	if len(a.Posts()) != 1 || len(a.Updates()) != 1 {
+		t.Errorf("Expected the first sink to post once and update once, got %d posts and %d updates", len(a.Posts()), len(a.Updates()))
+	}
+	if len(b.Posts()) != 1 || len(b.Updates()) != 0 {
+		t.Errorf("Expected the second sink to post once it recovered, got %d posts and %d updates", len(b.Posts()), len(b.Updates()))
+	}
+	for _, sink := range []*progresstest.Sink{a, b} {
+		if last := sink.Last(); last == nil || !last.Complete {
+			t.Errorf("Expected the last message to be complete, got %+v", last)
+		}
+	}
+}
+
+func TestMultiSinkRepostsOnlyTheSinkThatCantEdit(t *testing.T) {
+	a, b := &progresstest.Sink{}, &progresstest.Sink{}
+	pbar := newProgress(t, progress.NewMultiSink(a, b), progress.WithTask("release"))
+
+	if err := pbar.Update(50); err != nil {
+		t.Fatalf("Error updating progress bar: %s", err)
+	}
+
+	a.UpdateErr = progress.ErrCantEdit
+	if err := pbar.Update(100); err != nil {
+		t.Fatalf("Expected the message to be reposted, got %s", err)
+	}
+
+	if len(a.Posts()) != 2 {
+		t.Errorf("Expected the first sink to post again, got %d posts", len(a.Posts()))
+	}
+	if len(b.Posts()) != 1 || len(b.Updates()) != 1 {
+		t.Errorf("Expected the second sink to keep its message, got %d posts and %d updates", len(b.Posts()), len(b.Updates()))
+	}
+}
+
+func TestMultiSinkReset(t *testing.T) {
+	a, b := &progresstest.Sink{}, &progresstest.Sink{}
+	pbar := newProgress(t, progress.NewMultiSink(a, b), progress.WithTask("build"))
+
+	if err := pbar.Update(100); err != nil {
+		t.Fatalf("Error updating progress bar: %s", err)
+	}
+	pbar.Reset("deploy")
+	if err := pbar.Update(50); err != nil {
+		t.Fatalf("Error updating progress bar: %s", err)
+	}
+
+	for _, sink := range []*progresstest.Sink{a, b} {
+		if len(sink.Posts()) != 2 || len(sink.Updates()) != 0 {
+			t.Errorf("Expected a new message for the next task, got %d posts and %d updates", len(sink.Posts()), len(sink.Updates()))
+		}
+	}
+}
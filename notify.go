@@ -0,0 +1,51 @@
+package progress
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+)
+
+// Notifier is implemented by sinks that can send a notification about a
+// progress message, e.g. a threaded reply in slack. Unlike edits to the
+// progress message, notifications ping the people mentioned in them.
+type Notifier interface {
+	Notify(ctx context.Context, id, text string, mentions []string) error
+}
+
+// notify sends text with Options.Mentions if the sink is a Notifier and the
+// message has been posted. Errors are logged and passed to Options.OnError.
+// p.sendMu must be held but not p.mu.
+func (p *Progress) notify(ctx context.Context, id, text string) {
+	n, ok := p.sink.(Notifier)
+	if !ok || id == "" {
+		return
+	}
+
+	if err := n.Notify(ctx, id, text, p.Opts.Mentions); err != nil {
+		p.log(ctx, slog.LevelError, "Error sending notification", "error", err)
+		if p.Opts.OnError != nil {
+			p.Opts.OnError(err)
+		}
+	}
+}
+
+// notifyMilestones sends a notification if msg crossed one of
+// Options.Milestones since the last message, which was at prevPct. Only the
+// highest milestone crossed is notified. p.sendMu must be held but not p.mu.
+func (p *Progress) notifyMilestones(ctx context.Context, id string, prevPct int, msg *Message) {
+	crossed := -1
+	for _, m := range p.Opts.Milestones {
+		if m > prevPct && m <= msg.Pct && m > crossed {
+			crossed = m
+		}
+	}
+
+	switch {
+	case crossed < 0:
+	case crossed == 100:
+		p.notify(ctx, id, fmt.Sprintf("%s is complete", msg.Task))
+	default:
+		p.notify(ctx, id, fmt.Sprintf("%s is %d%% complete", msg.Task, crossed))
+	}
+}
@@ -0,0 +1,29 @@
+package progress_test
+
+import (
+	"testing"
+
+	"github.com/sfreiberg/progress"
+	"github.com/sfreiberg/progress/progresstest"
+)
+
+func TestMilestones(t *testing.T) {
+	pbar, sink := progresstest.New(t, progress.WithTask("import"), progress.WithMilestones(25, 50, 75, 100), progress.WithMentions("U123"))
+
+	for _, pos := range []int{10, 30, 80, 90, 100} {
+		if err := pbar.Update(pos); err != nil {
+			t.Fatalf("Error updating progress bar: %s", err)
+		}
+	}
+
+	want := []string{"import is 25% complete", "import is 75% complete", "import is complete"}
+	notes := sink.Notifications()
+	if len(notes) != len(want) {
+		t.Fatalf("Expected %d notifications, got %+v", len(want), notes)
+	}
+	for i, note := range notes {
+		if note.Text != want[i] || note.ID != "1" || note.Mentions[0] != "U123" {
+			t.Errorf("Expected notification %q, got %+v", want[i], note)
+		}
+	}
+}
@@ -0,0 +1,441 @@
+package progress
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"maps"
+	"net/http"
+	"slices"
+	"text/template"
+	"time"
+
+	"github.com/slack-go/slack"
+)
+
+// Option customizes a progress bar when it's created. *Options is an Option
+// too, so a complete Options struct can be passed to New and friends and
+// further customized by the options that follow it.
+type Option interface {
+	apply(opts *Options) *Options
+}
+
+// apply replaces the options built so far with o. A nil *Options leaves them
+// untouched.
+func (o *Options) apply(opts *Options) *Options {
+	if o == nil {
+		return opts
+	}
+	return o
+}
+
+// optionFunc changes a single field of the options built so far.
+type optionFunc func(opts *Options)
+
+func (f optionFunc) apply(opts *Options) *Options {
+	f(opts)
+	return opts
+}
+
+// buildOptions applies opts in order on top of DefaultOptions.
+func buildOptions(opts []Option) *Options {
+	o := DefaultOptions("Unknown Task")
+	for _, opt := range opts {
+		if opt != nil {
+			o = opt.apply(o)
+		}
+	}
+	return o
+}
+
+// clone returns a copy of o that shares no maps, slices or estimator state
+// with it, so progress bars created from the same options don't race. A
+// stateful Estimator such as EWMAEstimator is replaced by a new one with the
+// same settings.
+func (o *Options) clone() *Options {
+	c := *o
+	c.Funcs = maps.Clone(o.Funcs)
+	c.Actions = maps.Clone(o.Actions)
+	c.Fills = slices.Clone(o.Fills)
+	c.Milestones = slices.Clone(o.Milestones)
+	c.Mentions = slices.Clone(o.Mentions)
+	c.CompleteMentions = slices.Clone(o.CompleteMentions)
+	c.Buttons = slices.Clone(o.Buttons)
+	c.SlackMsgOptions = slices.Clone(o.SlackMsgOptions)
+	if est, ok := o.Estimator.(statefulEstimator); ok {
+		c.Estimator = est.fresh()
+	}
+	return &c
+}
+
+// WithTask sets the name of the task we are showing progress for.
+func WithTask(task string) Option {
+	return optionFunc(func(o *Options) { o.Task = task })
+}
+
+// WithWidth sets how many characters wide the progress bar is.
+func WithWidth(width int) Option {
+	return optionFunc(func(o *Options) { o.Width = width })
+}
+
+// WithFill sets the character(s) used to fill in the progress bar.
+func WithFill(fill string) Option {
+	return optionFunc(func(o *Options) { o.Fill = fill })
+}
+
+// WithEmpty sets the character(s) used to indicate empty space at the end of
+// the progress bar.
+func WithEmpty(empty string) Option {
+	return optionFunc(func(o *Options) { o.Empty = empty })
+}
+
+// WithTotal sets the total possible units.
+func WithTotal(units int) Option {
+	return optionFunc(func(o *Options) { o.TotalUnits = units })
+}
+
+// WithTemplate sets the text/template used to render the message.
+func WithTemplate(msg string) Option {
+	return optionFunc(func(o *Options) { o.Msg = msg })
+}
+
+// WithAsUser sets whether or not to post as the user.
+func WithAsUser(asUser bool) Option {
+	return optionFunc(func(o *Options) { o.AsUser = asUser })
+}
+
+// WithEstTime sets whether or not to show estimated time remaining.
+func WithEstTime(show bool) Option {
+	return optionFunc(func(o *Options) { o.ShowEstTime = show })
+}
+
+// WithSlackMsgOptions adds extra slack message options that are sent with
+// every post and update.
+func WithSlackMsgOptions(msgOpts ...slack.MsgOption) Option {
+	return optionFunc(func(o *Options) { o.SlackMsgOptions = append(o.SlackMsgOptions, msgOpts...) })
+}
+
+// WithBlocks sets whether or not to render the message as slack Block Kit
+// blocks.
+func WithBlocks(blocks bool) Option {
+	return optionFunc(func(o *Options) { o.Blocks = blocks })
+}
+
+// WithButtons adds buttons that are shown below the progress bar in Block Kit
+// mode.
+func WithButtons(buttons ...*slack.ButtonBlockElement) Option {
+	return optionFunc(func(o *Options) { o.Buttons = append(o.Buttons, buttons...) })
+}
+
+// WithThread posts the progress bar as a reply in the thread of the message
+// with timestamp ts. If broadcast is true the reply is also sent to the
+// channel.
+func WithThread(ts string, broadcast bool) Option {
+	return optionFunc(func(o *Options) {
+		o.ThreadTS = ts
+		o.Broadcast = broadcast
+	})
+}
+
+// WithFailure sets the character(s) used to fill in the progress bar and the
+// message template used when the task fails.
+func WithFailure(fill, msg string) Option {
+	return optionFunc(func(o *Options) {
+		o.FailFill = fill
+		o.FailMsg = msg
+	})
+}
+
+// WithTotal64 sets the total possible units for workloads that may not fit in
+// an int.
+func WithTotal64(units int64) Option {
+	return optionFunc(func(o *Options) { o.Total64 = units })
+}
+
+// WithEstimator sets how the time remaining is estimated.
+func WithEstimator(est Estimator) Option {
+	return optionFunc(func(o *Options) { o.Estimator = est })
+}
+
+// WithFuncs adds functions that are available in the message templates.
+func WithFuncs(funcs template.FuncMap) Option {
+	return optionFunc(func(o *Options) {
+		if o.Funcs == nil {
+			o.Funcs = template.FuncMap{}
+		}
+		for name, fn := range funcs {
+			o.Funcs[name] = fn
+		}
+	})
+}
+
+// WithMinInterval sets the minimum time between two updates.
+func WithMinInterval(d time.Duration) Option {
+	return optionFunc(func(o *Options) { o.MinInterval = d })
+}
+
+// WithRetry sets how many times a message is sent before giving up on
+// transient errors and how long to wait between attempts.
+func WithRetry(maxAttempts int, backoff, maxBackoff time.Duration) Option {
+	return optionFunc(func(o *Options) {
+		o.MaxAttempts = maxAttempts
+		o.RetryBackoff = backoff
+		o.MaxRetryBackoff = maxBackoff
+	})
+}
+
+// WithAsync sends messages from a background goroutine so Update never waits
+// on the network.
+func WithAsync() Option {
+	return optionFunc(func(o *Options) { o.Async = true })
+}
+
+// WithHooks sets the functions called after the progress bar is first posted,
+// after every following update, when the task reaches 100% and when sending a
+// message fails. Any of them may be nil.
+func WithHooks(onStart func(id string, msg *Message), onUpdate, onComplete func(msg *Message), onError func(err error)) Option {
+	return optionFunc(func(o *Options) {
+		o.OnStart = onStart
+		o.OnUpdate = onUpdate
+		o.OnComplete = onComplete
+		o.OnError = onError
+	})
+}
+
+// WithSendHook adds a function called after every message that was sent or
+// failed, with how long sending it took. See Options.OnSend. Any hook set
+// before, e.g. by progressprom, is kept and called first.
+func WithSendHook(onSend func(ctx context.Context, msg *Message, took time.Duration, err error)) Option {
+	return optionFunc(func(o *Options) {
+		prev := o.OnSend
+		if prev == nil {
+			o.OnSend = onSend
+			return
+		}
+		o.OnSend = func(ctx context.Context, msg *Message, took time.Duration, err error) {
+			prev(ctx, msg, took, err)
+			onSend(ctx, msg, took, err)
+		}
+	})
+}
+
+// WithLogger logs skipped updates, retries and errors sending messages to
+// logger.
+func WithLogger(logger *slog.Logger) Option {
+	return optionFunc(func(o *Options) { o.Logger = logger })
+}
+
+// WithMessageTS makes the progress bar edit the message posted earlier with
+// the timestamp ts instead of posting a new one. Slack needs the channel ID
+// rather than its name to edit a message.
+func WithMessageTS(ts string) Option {
+	return optionFunc(func(o *Options) { o.MessageTS = ts })
+}
+
+// WithRefresh re-sends the latest position every interval, even if it hasn't
+// changed, so the elapsed and remaining time stay current. Call
+// Progress.Close to stop it before the task ends.
+func WithRefresh(interval time.Duration) Option {
+	return optionFunc(func(o *Options) { o.Refresh = interval })
+}
+
+// WithStall shows the task as stalled once no progress has been made for
+// after. If notify is true a notification mentioning Options.Mentions is sent
+// too.
+func WithStall(after time.Duration, notify bool) Option {
+	return optionFunc(func(o *Options) {
+		o.StallAfter = after
+		o.StallNotify = notify
+	})
+}
+
+// WithMentions sets the users or groups mentioned in notifications.
+func WithMentions(userIDs ...string) Option {
+	return optionFunc(func(o *Options) { o.Mentions = userIDs })
+}
+
+// WithDeadline marks the task as overdue after deadline. If notify is true a
+// notification mentioning Options.Mentions is sent when it becomes overdue.
+func WithDeadline(deadline time.Time, notify bool) Option {
+	return optionFunc(func(o *Options) {
+		o.Deadline = deadline
+		o.OverdueNotify = notify
+	})
+}
+
+// WithMaxDuration marks the task as overdue once it has run for d. If notify
+// is true a notification mentioning Options.Mentions is sent when it becomes
+// overdue.
+func WithMaxDuration(d time.Duration, notify bool) Option {
+	return optionFunc(func(o *Options) {
+		o.MaxDuration = d
+		o.OverdueNotify = notify
+	})
+}
+
+// WithMilestones sends a notification mentioning Options.Mentions when any of
+// the percentages is crossed.
+func WithMilestones(pcts ...int) Option {
+	return optionFunc(func(o *Options) { o.Milestones = pcts })
+}
+
+// WithCompleteMentions mentions users or groups in the final message once the
+// task completes or fails. See Options.CompleteMentions for the accepted
+// formats.
+func WithCompleteMentions(mentions ...string) Option {
+	return optionFunc(func(o *Options) { o.CompleteMentions = mentions })
+}
+
+// WithCompleteMsg renders the tmpl summary template when the task completes,
+// e.g. DefaultCompleteMsg. If reply is true the summary is sent as a
+// notification (a threaded reply in slack) instead of replacing the final
+// message.
+func WithCompleteMsg(tmpl string, reply bool) Option {
+	return optionFunc(func(o *Options) {
+		o.CompleteMsg = tmpl
+		o.CompleteReply = reply
+	})
+}
+
+// WithCountBar draws the bar from the counts kept by IncSuccess, IncFailed and
+// IncSkipped, filled with success, failed and skipped.
+func WithCountBar(success, failed, skipped string) Option {
+	return optionFunc(func(o *Options) {
+		o.CountBar = true
+		o.SuccessFill = success
+		o.FailedFill = failed
+		o.SkippedFill = skipped
+	})
+}
+
+// WithLogLines sets the number of lines passed to Progress.Log that are shown.
+func WithLogLines(n int) Option {
+	return optionFunc(func(o *Options) { o.LogLines = n })
+}
+
+// WithTheme draws the bar with the theme registered under name, e.g. "moons".
+func WithTheme(name string) Option {
+	return optionFunc(func(o *Options) { o.Theme = name })
+}
+
+// WithSubBlocks draws the bar with Unicode block characters, including partial
+// blocks, so it can show progress smaller than a whole cell.
+func WithSubBlocks() Option {
+	return optionFunc(func(o *Options) { o.SubBlocks = true })
+}
+
+// WithRounding sets how partly filled cells of the progress bar are drawn.
+func WithRounding(r Rounding) Option {
+	return optionFunc(func(o *Options) { o.Rounding = r })
+}
+
+// WithUnit shows the position and total with a unit label, e.g. "3,214 /
+// 10,000 rows".
+func WithUnit(unit string) Option {
+	return optionFunc(func(o *Options) { o.Unit = unit })
+}
+
+// WithPrecision shows the percent with decimals, e.g. 42.7% with a precision
+// of 1.
+func WithPrecision(decimals int) Option {
+	return optionFunc(func(o *Options) { o.Precision = decimals })
+}
+
+// WithETA sets the time.Format layout and time zone of the estimated
+// completion time available to templates as .ETA. A nil loc means UTC.
+func WithETA(layout string, loc *time.Location) Option {
+	return optionFunc(func(o *Options) {
+		o.ETALayout = layout
+		o.Location = loc
+	})
+}
+
+// WithDurations formats the durations shown in messages with f, e.g. a
+// DurationUnits to translate them.
+func WithDurations(f DurationFormatter) Option {
+	return optionFunc(func(o *Options) { o.Durations = f })
+}
+
+// WithEphemeral posts the progress bar as ephemeral messages that only user
+// can see. Pass the response_url of the slash command or interaction that
+// started the task, if there is one, so the message is replaced instead of
+// posting a new one.
+func WithEphemeral(user, responseURL string) Option {
+	return optionFunc(func(o *Options) {
+		o.EphemeralUser = user
+		o.ResponseURL = responseURL
+	})
+}
+
+// WithReactions adds a reaction to the progress message that shows whether
+// the task is running, complete or failed.
+func WithReactions() Option {
+	return optionFunc(func(o *Options) { o.Reactions = true })
+}
+
+// WithCleanup deletes or summarizes the progress message after it has been
+// complete for the after duration. See Options.Cleanup.
+func WithCleanup(cleanup Cleanup, after time.Duration) Option {
+	return optionFunc(func(o *Options) {
+		o.Cleanup = cleanup
+		o.CleanupAfter = after
+	})
+}
+
+// WithHistory posts a new message every step percent instead of editing the
+// progress message.
+func WithHistory(step int) Option {
+	return optionFunc(func(o *Options) { o.History = step })
+}
+
+// WithMaxLength sets the longest message, in characters, that's sent. See
+// Options.MaxLength.
+func WithMaxLength(n int) Option {
+	return optionFunc(func(o *Options) { o.MaxLength = n })
+}
+
+// WithPermalink fetches a link to the progress message once it's posted. See
+// Progress.Permalink.
+func WithPermalink() Option {
+	return optionFunc(func(o *Options) { o.FetchPermalink = true })
+}
+
+// WithRegister lists the progress bar in Running so Handler, Dispatch,
+// SlashCommandHandler and Home can find it. See Options.Register.
+func WithRegister() Option {
+	return optionFunc(func(o *Options) { o.Register = true })
+}
+
+// WithHTTPClient sends requests to slack with client.
+func WithHTTPClient(client *http.Client) Option {
+	return optionFunc(func(o *Options) { o.HTTPClient = client })
+}
+
+// WithRenderer draws the progress bar with r instead of the built in bar.
+func WithRenderer(r Renderer) Option {
+	return optionFunc(func(o *Options) { o.Renderer = r })
+}
+
+// WithTerminal draws the progress bar on the terminal w instead of posting to
+// slack.
+func WithTerminal(w io.Writer) Option {
+	return optionFunc(func(o *Options) { o.Terminal = w })
+}
+
+// WithMetadata attaches machine readable message metadata to every message.
+// See Options.Metadata.
+func WithMetadata() Option {
+	return optionFunc(func(o *Options) { o.Metadata = true })
+}
+
+// WithAllowDecrease lets the position go backwards. See
+// Options.AllowDecrease.
+func WithAllowDecrease() Option {
+	return optionFunc(func(o *Options) { o.AllowDecrease = true })
+}
+
+// WithEscalation raises an alert with e when the task fails or stalls. See
+// Options.Escalator.
+func WithEscalation(e Escalator) Option {
+	return optionFunc(func(o *Options) { o.Escalator = e })
+}
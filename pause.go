@@ -0,0 +1,69 @@
+package progress
+
+import (
+	"context"
+	"time"
+)
+
+// Pause shows the task as paused, e.g. while it waits for a manual approval.
+// Time spent paused doesn't count towards the elapsed time, so the estimated
+// time remaining stays put until Resume is called. The message is re-rendered
+// subject to Options.MinInterval. Pausing a paused or finished task does
+// nothing.
+func (p *Progress) Pause() error {
+	return p.PauseContext(context.Background())
+}
+
+// PauseContext is like Pause but gives up on sending the message when ctx is
+// cancelled or times out.
+func (p *Progress) PauseContext(ctx context.Context) error {
+	return p.rerender(ctx, func() bool {
+		if !p.paused.IsZero() || p.done || p.lastPct == 100 {
+			return false
+		}
+		p.paused = time.Now()
+		p.rearmDeadline()
+		return true
+	})
+}
+
+// Resume continues a task paused with Pause.
+func (p *Progress) Resume() error {
+	return p.ResumeContext(context.Background())
+}
+
+// ResumeContext is like Resume but gives up on sending the message when ctx
+// is cancelled or times out.
+func (p *Progress) ResumeContext(ctx context.Context) error {
+	return p.rerender(ctx, func() bool {
+		if p.paused.IsZero() {
+			return false
+		}
+
+		now := time.Now()
+		p.pausedFor += now.Sub(p.paused)
+		p.paused = time.Time{}
+		// Waiting while paused isn't a stall
+		p.moved = now
+		p.stalled = false
+		p.rearmDeadline()
+		return true
+	})
+}
+
+// Paused returns whether the task is paused.
+func (p *Progress) Paused() bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return !p.paused.IsZero()
+}
+
+// elapsed returns how long the task has been running at now, not counting
+// the time it was paused. p.mu must be held.
+func (p *Progress) elapsed(now time.Time) time.Duration {
+	paused := p.pausedFor
+	if !p.paused.IsZero() {
+		paused += now.Sub(p.paused)
+	}
+	return now.Sub(p.Start) - paused
+}
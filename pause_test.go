@@ -0,0 +1,45 @@
+package progress_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sfreiberg/progress"
+	"github.com/sfreiberg/progress/progresstest"
+)
+
+func TestPause(t *testing.T) {
+	pbar, sink := progresstest.New(t, progress.WithTask("import"))
+	if err := pbar.Update(50); err != nil {
+		t.Fatalf("Error updating progress bar: %s", err)
+	}
+
+	if err := pbar.Pause(); err != nil {
+		t.Fatalf("Error pausing progress bar: %s", err)
+	}
+	if !pbar.Paused() || !sink.Last().Paused || !strings.Contains(sink.Last().Text, "⏸ paused") {
+		t.Errorf("Expected the message to show the task is paused, got %q", sink.Last().Text)
+	}
+	time.Sleep(100 * time.Millisecond)
+
+	if err := pbar.Resume(); err != nil {
+		t.Fatalf("Error resuming progress bar: %s", err)
+	}
+	msg := sink.Last()
+	if pbar.Paused() || msg.Paused || strings.Contains(msg.Text, "paused") {
+		t.Errorf("Expected the message not to show the task is paused, got %q", msg.Text)
+	}
+	if msg.Elapsed >= 50*time.Millisecond {
+		t.Errorf("Expected the time paused not to count, got %s elapsed", msg.Elapsed)
+	}
+
+	// Nothing changes when the task isn't paused
+	updates := len(sink.Updates())
+	if err := pbar.Resume(); err != nil {
+		t.Fatalf("Error resuming progress bar: %s", err)
+	}
+	if len(sink.Updates()) != updates {
+		t.Errorf("Expected no update when resuming a running task")
+	}
+}
@@ -0,0 +1,57 @@
+package progress
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/slack-go/slack"
+)
+
+// Permalinker is implemented by sinks that can link to a message they posted.
+type Permalinker interface {
+	Permalink(ctx context.Context, id string) (string, error)
+}
+
+// Permalink returns a link to the progress message, e.g. to print a "follow
+// progress here" link in CI logs, when Options.FetchPermalink is set. It's
+// empty until the first message has been posted or if the sink can't link to
+// messages. The link is fetched before Options.OnStart is called so it can be
+// used there.
+func (p *Progress) Permalink() string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.permalink
+}
+
+// fetchPermalink fetches the link to the message id if Options.FetchPermalink
+// is set and the sink is a Permalinker. Errors are logged and passed to
+// Options.OnError. p.sendMu must be held but not p.mu.
+func (p *Progress) fetchPermalink(ctx context.Context, id string) {
+	pl, ok := p.sink.(Permalinker)
+	if !ok || !p.Opts.FetchPermalink {
+		return
+	}
+
+	link, err := pl.Permalink(ctx, id)
+	if err != nil {
+		p.log(ctx, slog.LevelError, "Error fetching permalink", "error", err)
+		if p.Opts.OnError != nil {
+			p.Opts.OnError(err)
+		}
+		return
+	}
+
+	p.mu.Lock()
+	p.permalink = link
+	p.mu.Unlock()
+}
+
+// Permalink returns a link to the message ts.
+func (s *slackSink) Permalink(ctx context.Context, ts string) (string, error) {
+	if err := s.refreshToken(ctx); err != nil {
+		return "", err
+	}
+
+	link, err := s.client.GetPermalinkContext(ctx, &slack.PermalinkParameters{Channel: s.channel, Ts: ts})
+	return link, slackError(err)
+}
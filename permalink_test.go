@@ -0,0 +1,46 @@
+package progress
+
+import (
+	"net/url"
+	"testing"
+)
+
+func TestPermalink(t *testing.T) {
+	const link = "https://example.slack.com/archives/C123/p12"
+	var lookups int
+	sink, done := newTestSlackSink(t, DefaultOptions("deploy"), func(method string, form url.Values) string {
+		if method == "chat.getPermalink" {
+			lookups++
+			if form.Get("channel") != "C123" || form.Get("message_ts") != "1.2" {
+				t.Errorf("Expected a permalink to C123 1.2, got %v", form)
+			}
+			return `{"ok":true,"channel":"C123","permalink":"` + link + `"}`
+		}
+		return `{"ok":true,"channel":"C123","ts":"1.2"}`
+	})
+	defer done()
+
+	var pbar *Progress
+	var started string
+	pbar, err := NewWithSink(sink, WithMinInterval(0), WithPermalink(), WithHooks(func(id string, msg *Message) {
+		started = pbar.Permalink()
+	}, nil, nil, nil))
+	if err != nil {
+		t.Fatalf("Error creating progress bar: %s", err)
+	}
+	if pbar.Permalink() != "" {
+		t.Errorf("Expected no permalink before posting, got %q", pbar.Permalink())
+	}
+	for _, pos := range []int{50, 100} {
+		if err := pbar.Update(pos); err != nil {
+			t.Fatalf("Error updating progress bar: %s", err)
+		}
+	}
+
+	if started != link || pbar.Permalink() != link {
+		t.Errorf("Expected %q in OnStart and afterwards, got %q and %q", link, started, pbar.Permalink())
+	}
+	if lookups != 1 {
+		t.Errorf("Expected the permalink to be fetched once, got %d", lookups)
+	}
+}
@@ -0,0 +1,46 @@
+package progress
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Poll samples the position from fn right away and then every interval and
+// updates the progress bar with it, e.g. from a SELECT COUNT(*) or the depth
+// of a queue. It blocks until the task completes or fails, fn returns an
+// error, which Poll returns, or ctx is done. Errors sending messages are
+// logged and passed to Options.OnError but don't stop polling. An interval
+// that isn't positive is an error.
+func (p *Progress) Poll(ctx context.Context, interval time.Duration, fn func() (pos int, err error)) error {
+	if interval <= 0 {
+		return fmt.Errorf("Invalid poll interval %s, it must be greater than 0", interval)
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		pos, err := fn()
+		if err != nil {
+			return err
+		}
+		err = p.UpdateContext(ctx, pos)
+		if errors.Is(err, ErrMaxPosExceeded) || errors.Is(err, ErrNegativePos) {
+			return err
+		}
+
+		p.mu.Lock()
+		ended := p.done || p.lastPct == 100
+		p.mu.Unlock()
+		if ended {
+			return nil
+		}
+
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
@@ -0,0 +1,44 @@
+package progress_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/sfreiberg/progress/progresstest"
+)
+
+func TestPoll(t *testing.T) {
+	pbar, sink := progresstest.New(t)
+
+	var polls int
+	err := pbar.Poll(context.Background(), time.Millisecond, func() (int, error) {
+		polls++
+		return min(polls*30, 100), nil
+	})
+	if err != nil {
+		t.Fatalf("Error polling: %s", err)
+	}
+	if polls != 4 || !sink.Last().Complete {
+		t.Errorf("Expected polling to stop once the task completed, got %d polls", polls)
+	}
+
+	pbar, _ = progresstest.New(t)
+	want := errors.New("connection refused")
+	if err := pbar.Poll(context.Background(), time.Millisecond, func() (int, error) { return 0, want }); err != want {
+		t.Errorf("Expected %v, got %v", want, err)
+	}
+}
+
+func TestPollInvalidInterval(t *testing.T) {
+	pbar, sink := progresstest.New(t)
+
+	err := pbar.Poll(context.Background(), 0, func() (int, error) { return 50, nil })
+	if err == nil {
+		t.Fatal("Expected an error polling with an interval of 0")
+	}
+	if sink.Last() != nil {
+		t.Errorf("Expected nothing to be sent")
+	}
+}
@@ -0,0 +1,103 @@
+package progress
+
+import (
+	"errors"
+	"sync"
+)
+
+// Task wraps fn so it counts as a success or failure, see IncSuccess and
+// IncFailed, once it returns. Use it with an errgroup.Group or any other way
+// of running functions concurrently and call Done with the group's error at
+// the end:
+//
+//	g.Go(pbar.Task(func() error { return process(file) }))
+//	...
+//	pbar.Done(g.Wait())
+func (p *Progress) Task(fn func() error) func() error {
+	return func() error {
+		err := fn()
+		if err != nil {
+			p.IncFailed()
+		} else {
+			p.IncSuccess()
+		}
+		return err
+	}
+}
+
+// Done ends the task: it fails with err if err isn't nil and finishes
+// otherwise.
+func (p *Progress) Done(err error) error {
+	if err != nil {
+		return p.Fail(err)
+	}
+	return p.Finish()
+}
+
+// Pool runs functions on a limited number of goroutines and advances a
+// progress bar as each of them completes. The total is the number of
+// functions passed to Go. Functions that fail are counted with IncFailed and
+// don't stop the others.
+type Pool struct {
+	p     *Progress
+	sem   chan struct{} // Holds a value for every running function
+	wg    sync.WaitGroup
+	mu    sync.Mutex
+	count int     // Functions passed to Go
+	errs  []error // Errors returned by the functions
+}
+
+// NewPool creates a Pool that runs up to workers functions at a time and
+// reports to p. If workers isn't positive there's no limit.
+func NewPool(p *Progress, workers int) *Pool {
+	pool := &Pool{p: p}
+	if workers > 0 {
+		pool.sem = make(chan struct{}, workers)
+	}
+	return pool
+}
+
+// Go runs fn once a worker is free. It blocks while all workers are busy.
+func (pool *Pool) Go(fn func() error) {
+	pool.mu.Lock()
+	pool.count++
+	// One more than the functions so far so the bar can't complete before
+	// Wait is called
+	pool.p.SetTotal(pool.count + 1)
+	pool.mu.Unlock()
+
+	if pool.sem != nil {
+		pool.sem <- struct{}{}
+	}
+	pool.wg.Add(1)
+	task := pool.p.Task(fn)
+	go func() {
+		defer pool.wg.Done()
+		if pool.sem != nil {
+			defer func() { <-pool.sem }()
+		}
+
+		if err := task(); err != nil {
+			pool.mu.Lock()
+			pool.errs = append(pool.errs, err)
+			pool.mu.Unlock()
+		}
+	}()
+}
+
+// Wait waits for the functions passed to Go to return and ends the task with
+// Done. It returns the errors of the functions that failed joined with
+// errors.Join.
+func (pool *Pool) Wait() error {
+	pool.wg.Wait()
+
+	pool.mu.Lock()
+	err := errors.Join(pool.errs...)
+	if pool.count > 0 {
+		pool.p.SetTotal(pool.count)
+	}
+	pool.mu.Unlock()
+
+	pool.p.Done(err)
+	return err
+}
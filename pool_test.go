@@ -0,0 +1,53 @@
+package progress_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/sfreiberg/progress"
+	"github.com/sfreiberg/progress/progresstest"
+)
+
+func TestPool(t *testing.T) {
+	pbar, sink := progresstest.New(t)
+
+	pool := progress.NewPool(pbar, 3)
+	for i := 0; i < 10; i++ {
+		pool.Go(func() error {
+			if i%5 == 0 {
+				return fmt.Errorf("item %d failed", i)
+			}
+			return nil
+		})
+	}
+	err := pool.Wait()
+	if err == nil || err.Error() == "" {
+		t.Fatalf("Expected the errors of the failed items")
+	}
+
+	last := sink.Last()
+	if !last.Failed || last.Total != 10 {
+		t.Errorf("Expected the task to fail with a total of 10, got %+v", last)
+	}
+	if counts := last.Counts; counts.Success != 8 || counts.Failed != 2 {
+		t.Errorf("Expected 8 successes and 2 failures, got %+v", counts)
+	}
+}
+
+func TestTask(t *testing.T) {
+	pbar, sink := progresstest.New(t, progress.WithTotal(2))
+
+	errs := make(chan error, 2)
+	for _, err := range []error{nil, nil} {
+		go func() { errs <- pbar.Task(func() error { return err })() }()
+	}
+	err := errors.Join(<-errs, <-errs)
+	if err := pbar.Done(err); err != nil {
+		t.Fatalf("Error ending the task: %s", err)
+	}
+
+	if last := sink.Last(); !last.Complete || last.Counts.Success != 2 {
+		t.Errorf("Expected the task to complete with 2 successes, got %+v", last)
+	}
+}
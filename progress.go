@@ -1,13 +1,24 @@
-// Package progress is a small library for creating a progress bar in slack
+// Package progress is a small library for creating a progress bar in slack.
+// Messages are delivered through a Sink so the same progress bar can be sent
+// to other chat systems as well.
 package progress
 
 import (
+	"context"
 	"errors"
+	"io"
+	"log/slog"
+	"math"
+	"net/http"
+	"os"
+	"strconv"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"text/template"
 	"time"
 
-	"github.com/nlopes/slack"
+	"github.com/slack-go/slack"
 )
 
 var (
@@ -18,20 +29,269 @@ var (
 	// ErrNegativePos is returned when a negative value is passed to Progress.Update.
 	// All positions should be >= 0.
 	ErrNegativePos = errors.New("Invalid position")
+
+	// ErrInvalidTotal is returned when a total <= 0 is passed to Progress.SetTotal
+	// or Progress.SetTotal64.
+	ErrInvalidTotal = errors.New("Invalid total")
 )
 
 // Options can be used to customize look of the progress bar. DefaultOptions() has pretty good defaults.
 type Options struct {
-	Fill        string // The character(s) used to fill in the progress bar
-	Empty       string // The character(s) used to indicate empty space at the end of progress bar
-	Width       int    // How many characters wide the progress bar should be. A value of 10 looks good on slack phone clients.
-	TotalUnits  int    // Total possible units. Graph will always display 0-100%.
-	Msg         string // The message template that will be sent to slack. Uses text/template for creating templates.
-	Task        string // Name of the task we are showing progress for.
-	AsUser      bool   // Whether or not to post as the user. If false posts as a generic bot and doesn't show edited next to messages. If true the opposite of both is true. Defaults to false.
-	ShowEstTime bool   // Whether or not to show estimated time remaining
+	Fill        string            // The character(s) used to fill in the progress bar
+	Empty       string            // The character(s) used to indicate empty space at the end of progress bar
+	Width       int               // How many characters wide the progress bar should be. A value of 10 looks good on slack phone clients.
+	TotalUnits  int               // Total possible units. Graph will always display 0-100%.
+	Total64     int64             // Total possible units for workloads that may not fit in an int, e.g. bytes on 32-bit platforms. Used instead of TotalUnits when > 0.
+	Msg         string            // The message template that will be sent to slack. Uses text/template for creating templates. The humanBytes, humanDuration, comma and slackDate functions are available. Templates are compiled once and cached.
+	Task        string            // Name of the task we are showing progress for.
+	AsUser      bool              // Whether or not to post as the user. If false posts as a generic bot and doesn't show edited next to messages. If true the opposite of both is true. Defaults to false. Only used by slack.
+	ShowEstTime bool              // Whether or not to show estimated time remaining
+	FailFill    string            // The character(s) used to fill in the progress bar after Progress.Fail is called
+	FailMsg     string            // The message template that will be sent when Progress.Fail is called. The error is available as .Err.
+	Precision   int               // The number of decimals shown in the percent, e.g. 1 for 42.7%. Updates are sent whenever the percent shown changes.
+	ETALayout   string            // The time.Format layout of the estimated completion time available to templates as .ETA. Defaults to DefaultETALayout.
+	Location    *time.Location    // The time zone of times shown in messages. Defaults to UTC.
+	Durations   DurationFormatter // Formats the durations shown in messages, e.g. DurationUnits to translate them. Durations are shown as time.Duration.String if it's nil.
+	Unit        string            // The unit of the position, e.g. "files", "rows" or "MB". If set the message shows the position and total, e.g. "3,214 / 10,000 rows".
+	MessageTS   string            // The timestamp (or sink id) of a message posted earlier. The progress bar edits it instead of posting a new message. Slack needs the channel ID rather than its name to edit a message.
+
+	// Draw the bar from the counts kept by IncSuccess, IncFailed and
+	// IncSkipped, each filled with its own character, instead of Fill.
+	CountBar    bool
+	SuccessFill string
+	FailedFill  string
+	SkippedFill string
+
+	// The summary template rendered when the task completes, e.g.
+	// DefaultCompleteMsg. It replaces Msg in the final message or, if
+	// CompleteReply is set, is sent as a notification (a threaded reply in
+	// slack) instead. Empty disables the summary.
+	CompleteMsg   string
+	CompleteReply bool
+
+	// What happens to the message once the task completes. CleanupDelete
+	// deletes it and CleanupSummary replaces it with CompleteMsg, or
+	// DefaultCleanupMsg if that's empty, so finished bars don't litter the
+	// channel. If CleanupAfter is set the message is left alone that long
+	// first. Close cancels a cleanup that hasn't happened yet.
+	Cleanup      Cleanup
+	CleanupAfter time.Duration
+
+	// Fetch a link to the progress message once it has been posted, which
+	// is returned by Progress.Permalink. It costs an extra request so it's
+	// off by default.
+	FetchPermalink bool
+
+	// List the progress bar in Running so Handler, Dispatch,
+	// SlashCommandHandler and Home can find it. It's listed until its task
+	// completes or fails or Close is called, which also keeps it from being
+	// garbage collected, so Close bars that are abandoned early. Off by
+	// default.
+	Register bool
+
+	// Post a new message every History percent, and when the task ends,
+	// instead of editing the message in place. Use it where edits are
+	// disabled or to keep a trail of the progress over time. Zero edits the
+	// message.
+	History int
+
+	// Draws the progress bar instead of the built in bar, e.g. a sparkline
+	// or just the numbers. Fill, Empty, themes and the other options for
+	// drawing the bar are only used by the built in bar.
+	Renderer Renderer
+
+	// The name of a theme registered with RegisterTheme, e.g. "moons". The
+	// theme replaces Fill, Empty, Fills, BarLeft, BarRight and, if the theme
+	// sets it, FailFill when the progress bar is created.
+	Theme string
+
+	// Fills picked by percent complete instead of Fill, e.g. red, yellow and
+	// green.
+	Fills []string
+
+	// How the number of filled cells is rounded. Defaults to RoundDown so
+	// the bar is only full at 100%.
+	Rounding Rounding
+
+	// Draw the bar with Unicode block characters, including partial blocks,
+	// so each cell can show eighths of its share. Fill is ignored. Emoji can't
+	// be split so this is off by default.
+	SubBlocks bool
+
+	// Drawn before and after the bar, e.g. "[" and "]".
+	BarLeft  string
+	BarRight string
+
+	// Extra functions available in the message templates. They
+	// override the built in functions with the same name. Changes after the
+	// progress bar is created only apply to templates compiled afterwards.
+	Funcs template.FuncMap
+
+	// Let the position go backwards, e.g. when items are retried or rolled
+	// back, and re-render the bar when it does. Without it lower positions
+	// are ignored. Add continues from the last position passed to Update.
+	AllowDecrease bool
+
+	// The minimum time between two updates. Updates that arrive sooner are
+	// skipped and the latest progress is sent with the next update after the
+	// interval. The final message is always sent.
+	MinInterval time.Duration
+
+	// Re-send the latest position this often, even if it hasn't changed, so
+	// the elapsed and remaining time stay current during slow phases. It stops
+	// once the task completes or fails or when Progress.Close is called. Zero
+	// disables it.
+	Refresh time.Duration
+
+	// Show the task as stalled once no progress has been made for this long
+	// and call OnStall. If StallNotify is set a notification mentioning
+	// Mentions is sent too, as a threaded reply in slack. Zero disables stall
+	// detection.
+	StallAfter  time.Duration
+	StallNotify bool
+	OnStall     func(msg *Message)
+
+	// The task is overdue after Deadline or once it has run for MaxDuration,
+	// whichever comes first. Overdue tasks are drawn with OverdueFill and show
+	// how late they are. Time spent paused doesn't count towards
+	// MaxDuration. If OverdueNotify is set a notification mentioning Mentions
+	// is sent when the task becomes overdue, or once the first message is
+	// posted if that's later.
+	Deadline      time.Time
+	MaxDuration   time.Duration
+	OverdueFill   string
+	OverdueNotify bool
+
+	// Percentages that send a notification mentioning Mentions when they're
+	// crossed, e.g. 25, 50, 75 and 100.
+	Milestones []int
+
+	// The number of lines passed to Progress.Log that are shown. Defaults to
+	// DefaultLogLines.
+	LogLines int
+
+	// The longest message, in characters, that's sent. Longer messages drop
+	// log lines, oldest first, and then the status until they fit. Defaults
+	// to DefaultMaxLength.
+	MaxLength int
+
+	// Users or groups mentioned in notifications, in the same formats as
+	// CompleteMentions.
+	Mentions []string
+
+	// Users or groups mentioned in the final message once the task completes
+	// or fails. Entries may be user IDs (U123), user group IDs (S123), here,
+	// channel or everyone, already formatted mentions (<@U123>) or @names,
+	// which slack links itself. Only used by slack.
+	CompleteMentions []string
+
+	// Raises an alert, e.g. with NewPagerDuty or NewOpsgenie, when the task
+	// fails or stalls. Errors are passed to OnError.
+	Escalator Escalator
+
+	// Draw the progress bar on this terminal, e.g. os.Stderr, instead of
+	// posting to slack. New also draws on os.Stderr when the token is empty
+	// so developers iterating locally don't spam a real channel.
+	Terminal io.Writer
+
+	// Logger logs skipped updates, retries and errors sending messages. Nothing
+	// is logged if it's nil.
+	Logger *slog.Logger
+
+	// Hooks called after the progress bar is first posted, after every
+	// following update, when the task reaches 100% and when sending a
+	// message fails. OnSend is called after every message that was sent or
+	// failed with the context it was sent with and how long it took,
+	// including retries. They're called from
+	// the goroutine sending the message and must not call methods of the
+	// Progress other than Permalink.
+	OnStart    func(id string, msg *Message)
+	OnUpdate   func(msg *Message)
+	OnComplete func(msg *Message)
+	OnError    func(err error)
+	OnSend     func(ctx context.Context, msg *Message, took time.Duration, err error)
+
+	// Send messages from a background goroutine so Update never waits on the
+	// network. Only the latest position is sent, at most once per
+	// MinInterval. Call Progress.Close when done to send the last position.
+	Async bool
+
+	// How many times a message is sent before giving up on network errors
+	// and 5xx responses. Retries wait RetryBackoff, doubling with every
+	// attempt up to MaxRetryBackoff unless it's 0, with some random jitter. A
+	// MaxAttempts of 0 or 1 disables retries.
+	MaxAttempts     int
+	RetryBackoff    time.Duration
+	MaxRetryBackoff time.Duration
+
+	// How far back Rate is measured. Defaults to DefaultRateWindow when 0.
+	RateWindow time.Duration
+
+	// Estimates the time remaining. Defaults to LinearEstimator when nil. Use
+	// NewEWMAEstimator for tasks whose throughput changes over time.
+	Estimator Estimator
+
+	// Render the message as slack Block Kit blocks instead of plain text. The
+	// rendered Msg template is still sent as the notification text. Only used
+	// by slack.
+	Blocks bool
+
+	// Post the progress bar as a reply in the thread of the message with this
+	// timestamp. If Broadcast is true the reply is also sent to the channel.
+	// Only used by slack.
+	ThreadTS  string
+	Broadcast bool
+
+	// Buttons shown below the progress bar when Blocks is true. Only used by
+	// slack.
+	Buttons []*slack.ButtonBlockElement
+
+	// Callbacks keyed by the action id of Buttons, called by
+	// Progress.HandleInteraction when the button is pressed. Only used by
+	// slack.
+	Actions map[string]func(cb *slack.InteractionCallback)
+
+	// Add a reaction to the progress message showing the state of the task:
+	// ⏳ while it's running, ✅ once it completes and ❌ if it fails. Needs
+	// the reactions:write scope. Only used by slack.
+	Reactions bool
+
+	// Attach message metadata with the MetadataEventType event type and the
+	// position, total, percent and estimated completion time to every
+	// message so other apps and workflows can follow the progress without
+	// parsing the text. Only used by slack.
+	Metadata bool
+
+	// Post ephemeral messages that only this user can see, e.g. the user
+	// who ran a slash command. Slack can't edit ephemeral messages so a
+	// message is only sent every DefaultWebhookStep percent. If ResponseURL,
+	// the response_url of the slash command or interaction, is set the
+	// message is replaced through it. Otherwise a new message is posted each
+	// time. Only used by slack.
+	EphemeralUser string
+	ResponseURL   string
+
+	// The HTTP client used to talk to slack, e.g. one with timeouts, a proxy
+	// that needs authentication or custom TLS settings. Defaults to
+	// http.DefaultClient, which uses the proxy in HTTPS_PROXY. Only used by
+	// slack.
+	HTTPClient *http.Client
+
+	// Extra slack message options (metadata, icons, etc.) that are sent with
+	// every post and update. Only used by slack.
+	SlackMsgOptions []slack.MsgOption
 }
 
+// DefaultCompleteMsg is a summary template for Options.CompleteMsg with the
+// duration, the number of units processed and the average rate.
+const DefaultCompleteMsg = "*{{.Task}}* completed in *{{.Elapsed}}*\n" +
+	"Processed {{comma .Current}} of {{comma .Total}} at {{printf \"%.1f\" .AvgRate}}/s" +
+	"{{ if .Counts.Failed }}, {{comma .Counts.Failed}} failed{{ end }}"
+
+// DefaultCleanupMsg is the one line summary that replaces the message when
+// Options.Cleanup is CleanupSummary and there's no Options.CompleteMsg.
+const DefaultCleanupMsg = "✅ *{{.Task}}* completed in *{{.Elapsed}}*"
+
 // DefaultOptions creates an Options struct with decent defaults.
 func DefaultOptions(task string) *Options {
 	return &Options{
@@ -39,119 +299,807 @@ func DefaultOptions(task string) *Options {
 		Empty:      "⬜",
 		Width:      10, // Looks good on slack phone clients
 		TotalUnits: 100,
-		Msg: "{{.Task}}\n`{{.ProgBar}}` {{.Pos}}%\n" +
+		Msg: "{{.Task}}{{ if .Phase }} ({{ .Phase }}){{ end }}\n`{{.ProgBar}}` {{.Pos}}%" +
+			"{{ if .Unit }} ({{ comma .Current }} / {{ comma .Total }} {{ .Unit }}){{ end }}\n" +
+			"{{ if .Status }}_{{ .Status }}_\n{{ end }}" +
 			"{{ if .ShowEstTime }}" +
 			"{{ if .Complete }}Completed in *{{ .Elapsed }}*" +
 			"{{ else }}{{ .Remaining }} remaining...{{ end }}" +
-			"{{ end }}",
-		Task:        task,
-		ShowEstTime: true,
+			"{{ end }}" +
+			"{{ if .Stalled }}\n⚠️ stalled for {{ .Stalled }}{{ end }}" +
+			"{{ if .Overdue }}\n🟥 overdue by {{ .Overdue }}{{ end }}" +
+			"{{ if .Paused }}\n⏸ paused{{ end }}" +
+			"{{ if .Log }}\n```\n{{ .Log }}\n```{{ end }}",
+		Task:            task,
+		ShowEstTime:     true,
+		FailFill:        "❌",
+		OverdueFill:     "🟥",
+		SuccessFill:     "🟩",
+		FailedFill:      "🟥",
+		SkippedFill:     "⬛",
+		MinInterval:     time.Second, // Slack allows about one update per second
+		MaxAttempts:     3,
+		RetryBackoff:    500 * time.Millisecond,
+		MaxRetryBackoff: 10 * time.Second,
+		FailMsg: "{{.Task}}{{ if .Phase }} ({{ .Phase }}){{ end }}\n`{{.ProgBar}}` {{.Pos}}%\n" +
+			"Failed after *{{ .Elapsed }}*: {{ .Err }}",
 	}
 }
 
-// Progress is a struct that creates the progress bar in slack
+// Progress is a struct that creates the progress bar in slack. It is safe to
+// call Update and Add from multiple goroutines.
 type Progress struct {
-	Opts    *Options
-	Start   time.Time     // When the task began running. Initialized to current time when New() is called.
-	client  *slack.Client // Slack client
-	channel string        // Channel to post progress bar to
-	ts      string        // The last timestamp we saw. Used for editing the progress bar
-	lastPct int           // The last percent that was posted to slack. No reason to update if nothing has changed.
+	Opts     *Options
+	Start    time.Time       // When the task began running. Initialized to current time when New() is called.
+	count    atomic.Int64    // The highest position seen by Update or Add
+	tally    [3]atomic.Int64 // Success, failed and skipped counts
+	sendMu   sync.Mutex      // Held while a message is sent so messages go out in order. Lock before mu.
+	mu       sync.Mutex      // Guards the fields below. Released while a message is sent.
+	sink     Sink            // Where messages are delivered
+	id       string          // The id of the message returned by the sink. Used for editing the progress bar
+	lastPos  int64           // The last position that was posted
+	lastPct  int             // The last percent that was posted
+	lastStep int64           // The last percent that was posted with Options.Precision. No reason to update if nothing has changed.
+	resend   bool            // Send the next update even if the percent hasn't changed
+	rates    rateWindow      // Recent positions for calculating the rate
+	async    *sender         // Background sender when Options.Async is set
+	pending  int64           // The latest position waiting for the background sender
+
+	phase    string    // The part of the task that's running
+	segments []Segment // Set by SetSegments
+	status   string    // Set by SetStatus
+	logTail  []string  // The last lines passed to Log
+	moved    time.Time // When the position last increased
+	movedPos int64     // The position when it last increased
+	stalled  bool      // Whether the stall has been reported
+	children []*child  // Child progress bars created with NewChild
+
+	overdueNotify bool // The deadline passed before the first post, which sends the overdue notification
+
+	lastSent  time.Time // When the last message was sent
+	notBefore time.Time // Don't send before this time because we've been rate limited. Guarded by sendMu.
+
+	paused    time.Time     // When Pause was called. Zero unless the task is paused.
+	pausedFor time.Duration // Time spent paused before the last Resume
+
+	templates map[string]*template.Template // Compiled templates keyed by their source
+	permalink string                        // Link to the message if the sink is a Permalinker
+	done      bool                          // Set once the task has failed. No more updates are sent after that.
+	err       error                         // The error passed to Fail
+
+	aborted   chan struct{} // Closed when the task has been asked to abort
+	abortOnce sync.Once
+	closed    chan struct{} // Closed by Close to stop the background goroutines
+	closeOnce sync.Once
+	reset     chan struct{} // Closed by Reset to stop the background goroutines of the previous task
+	rearm     chan struct{} // Wakes deadlineLoop up when the deadline may have moved
 }
 
 // Update either posts a new progress bar if this is the first call or updates an existing progress bar.
 func (p *Progress) Update(pos int) error {
+	return p.UpdateContext(context.Background(), pos)
+}
+
+// UpdateContext is like Update but gives up on sending the message when ctx is
+// cancelled or times out.
+func (p *Progress) UpdateContext(ctx context.Context, pos int) error {
+	return p.Update64Context(ctx, int64(pos))
+}
+
+// Update64 is like Update but takes a 64-bit position for workloads such as
+// byte counts that may not fit in an int.
+func (p *Progress) Update64(pos int64) error {
+	return p.Update64Context(context.Background(), pos)
+}
+
+// Update64Context is like Update64 but gives up on sending the message when
+// ctx is cancelled or times out.
+func (p *Progress) Update64Context(ctx context.Context, pos int64) error {
 	if pos < 0 {
 		return ErrNegativePos
 	}
 
-	if pos > p.Opts.TotalUnits {
+	// In async mode the background sender does the sending
+	if p.async == nil {
+		p.sendMu.Lock()
+		defer p.sendMu.Unlock()
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	total := p.total()
+	if pos > total {
 		return ErrMaxPosExceeded
 	}
 
-	pct := int(float32(pos) / float32(p.Opts.TotalUnits) * 100)
-	if pct <= p.lastPct { // We haven't progressed so no need to update slack
+	now := time.Now()
+	if pos > p.movedPos {
+		p.moved = now
+		p.movedPos = pos
+		p.stalled = false
+	}
+
+	if p.Opts.AllowDecrease {
+		// Going backwards isn't progress but moving forward again is
+		p.movedPos = min(p.movedPos, pos)
+		p.count.Store(pos)
+	}
+	for count := p.count.Load(); pos > count; count = p.count.Load() {
+		if p.count.CompareAndSwap(count, pos) {
+			break
+		}
+	}
+
+	p.rates.add(now, pos, p.rateWindow())
+
+	if p.async != nil {
+		p.pending = pos
+		p.async.wake()
 		return nil
 	}
 
-	msg, err := p.msg(pct)
-	if err != nil {
+	return p.update(ctx, pos, total)
+}
+
+// update sends pos if the percent has changed since the last message.
+// p.sendMu and p.mu must be held.
+func (p *Progress) update(ctx context.Context, pos, total int64) error {
+	step := p.step(pos, total)
+
+	unchanged := step == p.lastStep || (step < p.lastStep && !p.Opts.AllowDecrease)
+	if p.done || (unchanged && !p.resend) { // We haven't progressed so no need to update slack
+		return nil
+	}
+
+	msg, tmpl := p.message(pos, total, true)
+	return p.send(ctx, msg, tmpl)
+}
+
+// message creates the message for pos and returns it with the template it's
+// rendered with. sample is false for callers that only read the progress bar
+// so they don't feed the estimator. p.mu must be held.
+func (p *Progress) message(pos, total int64, sample bool) (*Message, string) {
+	pct := percent(pos, total)
+	step := p.step(pos, total)
+	now := time.Now()
+	elapsed := p.elapsed(now)
+	msg := &Message{
+		Task:      p.Opts.Task,
+		Phase:     p.phase,
+		Status:    p.status,
+		Log:       append([]string(nil), p.logTail...),
+		Pos:       pos,
+		Total:     total,
+		Pct:       pct,
+		Percent:   float64(step) / math.Pow10(p.Opts.Precision),
+		Complete:  pct == 100,
+		Paused:    !p.paused.IsZero(),
+		Start:     p.Start,
+		Elapsed:   elapsed.Round(time.Millisecond),
+		Remaining: p.remaining(pos, total, sample),
+		Rate:      p.rates.rate(now, pos),
+		Counts:    p.counts(),
+	}
+	if msg.Remaining > 0 {
+		msg.ETA = now.Add(msg.Remaining)
+	}
+	if elapsed > 0 {
+		msg.AvgRate = float64(pos) / elapsed.Seconds()
+	}
+	if stalled := now.Sub(p.moved); p.Opts.StallAfter > 0 && stalled >= p.Opts.StallAfter && !msg.Complete && !msg.Paused {
+		msg.Stalled = stalled.Round(time.Millisecond)
+	}
+	if deadline := p.deadline(now); !deadline.IsZero() && now.After(deadline) {
+		// At least a millisecond so the timer firing right at the deadline
+		// doesn't round down to on time
+		msg.Overdue = max(now.Sub(deadline).Round(time.Millisecond), time.Millisecond)
+	}
+	msg.Bar = p.renderer().Render(State{
+		Pos:      pos,
+		Total:    total,
+		Pct:      pct,
+		Percent:  msg.Percent,
+		Width:    p.Opts.Width,
+		Complete: msg.Complete,
+		Overdue:  msg.Overdue > 0,
+		Counts:   msg.Counts,
+		Segments: p.segments,
+	})
+
+	tmpl := p.Opts.Msg
+	if msg.Complete && p.Opts.CompleteMsg != "" && !p.Opts.CompleteReply {
+		tmpl = p.Opts.CompleteMsg
+	}
+	return msg, tmpl
+}
+
+// Add advances the position by n and updates the progress bar. The position
+// continues from the highest position passed to Update or Add so far, or the
+// last one if Options.AllowDecrease is set.
+func (p *Progress) Add(n int) error {
+	return p.AddContext(context.Background(), n)
+}
+
+// AddContext is like Add but gives up on sending the message when ctx is
+// cancelled or times out.
+func (p *Progress) AddContext(ctx context.Context, n int) error {
+	return p.Add64Context(ctx, int64(n))
+}
+
+// Add64 is like Add but takes a 64-bit amount.
+func (p *Progress) Add64(n int64) error {
+	return p.Add64Context(context.Background(), n)
+}
+
+// Add64Context is like Add64 but gives up on sending the message when ctx is
+// cancelled or times out. The position is left alone if it would end up out
+// of range.
+func (p *Progress) Add64Context(ctx context.Context, n int64) error {
+	for {
+		count := p.count.Load()
+		pos := count + n
+		if pos < 0 {
+			return ErrNegativePos
+		}
+
+		p.mu.Lock()
+		total := p.total()
+		p.mu.Unlock()
+		if pos > total {
+			return ErrMaxPosExceeded
+		}
+
+		if p.count.CompareAndSwap(count, pos) {
+			return p.Update64Context(ctx, pos)
+		}
+	}
+}
+
+// Increment advances the position by one and updates the progress bar.
+func (p *Progress) Increment() error {
+	return p.Add(1)
+}
+
+// SetTotal changes the total possible units, e.g. when the amount of work is
+// only discovered while the task is running. The percent and time remaining
+// are calculated against the new total on the next update, even if that
+// means the progress bar moves backwards.
+func (p *Progress) SetTotal(n int) error {
+	if n <= 0 {
+		return ErrInvalidTotal
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.Opts.TotalUnits = n
+	p.Opts.Total64 = 0
+	p.resend = true
+	return nil
+}
+
+// SetTotal64 is like SetTotal but takes a 64-bit total, which is stored in
+// Options.Total64.
+func (p *Progress) SetTotal64(n int64) error {
+	if n <= 0 {
+		return ErrInvalidTotal
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.Opts.Total64 = n
+	p.resend = true
+	return nil
+}
+
+// total returns the total possible units. p.mu must be held.
+func (p *Progress) total() int64 {
+	if p.Opts.Total64 > 0 {
+		return p.Opts.Total64
+	}
+	return int64(p.Opts.TotalUnits)
+}
+
+// percent calculates how many percent pos is of total without losing
+// precision for large totals.
+func percent(pos, total int64) int {
+	if pos > math.MaxInt64/100 {
+		return int(float64(pos) / float64(total) * 100)
+	}
+	return int(pos * 100 / total)
+}
+
+// DefaultETALayout is the layout of .ETA unless Options.ETALayout says
+// otherwise.
+const DefaultETALayout = "15:04 MST"
+
+// formatETA returns the estimated completion time of msg, e.g. "~14:32 UTC",
+// or an empty string if there's no estimate.
+func (p *Progress) formatETA(msg *Message) string {
+	if msg.ETA.IsZero() || msg.Complete || msg.Failed {
+		return ""
+	}
+
+	layout := p.Opts.ETALayout
+	if layout == "" {
+		layout = DefaultETALayout
+	}
+	loc := p.Opts.Location
+	if loc == nil {
+		loc = time.UTC
+	}
+	return "~" + msg.ETA.In(loc).Format(layout)
+}
+
+// endTime returns when the task of msg completed or failed or the zero time
+// if it's still running.
+func endTime(start time.Time, msg *Message) time.Time {
+	if !msg.Complete && !msg.Failed {
+		return time.Time{}
+	}
+	return start.Add(msg.Elapsed)
+}
+
+// formatPercent returns msg.Pct, or msg.Percent with precision decimals if
+// precision is positive.
+func formatPercent(msg *Message, precision int) interface{} {
+	if precision <= 0 {
+		return msg.Pct
+	}
+	return strconv.FormatFloat(msg.Percent, 'f', precision, 64)
+}
+
+// step returns the percent complete in units of the last decimal shown with
+// Options.Precision, e.g. 427 for 42.7% with a precision of 1.
+func (p *Progress) step(pos, total int64) int64 {
+	scale := int64(100 * math.Pow10(p.Opts.Precision))
+	if pos > math.MaxInt64/scale {
+		return int64(float64(pos) / float64(total) * float64(scale))
+	}
+	return pos * scale / total
+}
+
+// MessageTS returns the timestamp of the slack message, or the id returned by
+// the sink, that the progress bar edits. It's empty until the first message
+// has been posted. Pass it to WithMessageTS to resume the progress bar after a
+// restart.
+func (p *Progress) MessageTS() string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.id
+}
+
+// Finish sets the progress bar to 100% and shows the completion message.
+func (p *Progress) Finish() error {
+	return p.FinishContext(context.Background())
+}
+
+// FinishContext is like Finish but gives up on sending the message when ctx
+// is cancelled or times out.
+func (p *Progress) FinishContext(ctx context.Context) error {
+	p.sendMu.Lock()
+	defer p.sendMu.Unlock()
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	total := p.total()
+	p.count.Store(total)
+	return p.update(ctx, total, total)
+}
+
+// Fail shows that the task failed with err. The progress bar keeps its last
+// position but is filled with FailFill and rendered with the FailMsg
+// template. Updates after Fail are ignored.
+func (p *Progress) Fail(err error) error {
+	return p.FailContext(context.Background(), err)
+}
+
+// FailContext is like Fail but gives up on sending the message when ctx is
+// cancelled or times out.
+func (p *Progress) FailContext(ctx context.Context, err error) error {
+	p.sendMu.Lock()
+	defer p.sendMu.Unlock()
+	p.mu.Lock()
+
+	p.done = true
+	p.err = err
+
+	msg := p.failMessage(err)
+	sendErr := p.send(ctx, msg, p.Opts.FailMsg)
+	p.mu.Unlock()
+
+	p.escalate(ctx, msg)
+	return sendErr
+}
+
+// failMessage creates the message for a task that failed with err. p.mu must
+// be held.
+func (p *Progress) failMessage(err error) *Message {
+	msg := &Message{
+		Task:    p.Opts.Task,
+		Phase:   p.phase,
+		Status:  p.status,
+		Pos:     p.lastPos,
+		Total:   p.total(),
+		Pct:     p.lastPct,
+		Percent: float64(p.lastStep) / math.Pow10(p.Opts.Precision),
+		Failed:  true,
+		Err:     err,
+		Start:   p.Start,
+		Elapsed: p.elapsed(time.Now()).Round(time.Millisecond),
+		Counts:  p.counts(),
+	}
+	msg.Bar = p.renderer().Render(State{
+		Pos:      msg.Pos,
+		Total:    msg.Total,
+		Pct:      msg.Pct,
+		Percent:  msg.Percent,
+		Width:    p.Opts.Width,
+		Failed:   true,
+		Counts:   msg.Counts,
+		Segments: p.segments,
+	})
+	return msg
+}
+
+// send renders msg with the tmpl template and delivers it. Updates are
+// skipped while rate limited or when the last message was sent less than
+// MinInterval ago. The final message is always sent. p.sendMu and p.mu must
+// be held. p.mu is released while the message is delivered so Update
+// doesn't wait on the network in async mode.
+func (p *Progress) send(ctx context.Context, msg *Message, tmpl string) error {
+	final := msg.Complete || msg.Failed
+	now := time.Now()
+
+	if !final && (now.Before(p.notBefore) || now.Sub(p.lastSent) < p.Opts.MinInterval) {
+		p.log(ctx, slog.LevelDebug, "Skipping throttled update", "pct", msg.Pct)
+		return nil // The next update will include this progress
+	}
+	if p.Opts.History > 0 && p.id != "" && !final && msg.Pct/p.Opts.History <= p.lastPct/p.Opts.History {
+		return nil // Only milestones are posted in history mode
+	}
+
+	var err error
+	if err = p.renderFit(msg, tmpl); err != nil {
+		p.log(ctx, slog.LevelError, "Error rendering message", "error", err)
 		return err
 	}
 
-	// If there's no timestamp this is the first time we've run so post a normal message
-	if p.ts == "" {
-		msgOpts := []slack.MsgOption{
-			slack.MsgOptionText(msg, false),
-			slack.MsgOptionAsUser(p.Opts.AsUser),
+	var summary string
+	if msg.Complete && p.Opts.CompleteMsg != "" && p.Opts.CompleteReply {
+		if summary, err = p.render(msg, p.Opts.CompleteMsg); err != nil {
+			p.log(ctx, slog.LevelError, "Error rendering message", "error", err)
+			return err
+		}
+	}
+
+	var cleanupText string
+	if msg.Complete && p.Opts.Cleanup == CleanupSummary {
+		tmpl := p.Opts.CompleteMsg
+		if tmpl == "" {
+			tmpl = DefaultCleanupMsg
 		}
-		p.channel, p.ts, _, err = p.client.SendMessage(p.channel, msgOpts...)
+		if cleanupText, err = p.render(msg, tmpl); err != nil {
+			p.log(ctx, slog.LevelError, "Error rendering message", "error", err)
+			return err
+		}
+	}
+
+	prevID := p.id
+	first := prevID == ""
+	prevPct := p.lastPct
+	notifyOverdue := first && p.overdueNotify
+
+	p.mu.Unlock()
+	start := time.Now()
+	id, sent, err := p.deliver(ctx, msg, final)
+	if final {
+		unregister(p)
+	}
+	if p.Opts.OnSend != nil && (sent || err != nil) {
+		p.Opts.OnSend(ctx, msg, time.Since(start), err)
+	}
+	if sent {
+		if id != prevID {
+			p.fetchPermalink(ctx, id)
+		}
+		p.callHooks(id, first, msg)
+		p.notifyMilestones(ctx, id, prevPct, msg)
+		if summary != "" {
+			p.notify(ctx, id, summary)
+		}
+		if notifyOverdue {
+			p.notifyOverdue(ctx, id)
+		}
+		if msg.Complete && p.Opts.Cleanup != CleanupNone {
+			p.cleanup(ctx, id, msg, cleanupText)
+		}
+	} else if err != nil {
+		p.log(ctx, slog.LevelError, "Error sending message", "error", err)
+		if p.Opts.OnError != nil {
+			p.Opts.OnError(err)
+		}
+	}
+	p.mu.Lock()
+
+	if err != nil && id != "" {
+		// Some sinks, e.g. a multi sink, can post the message in part. Keep
+		// the id so the rest is updated instead of posted again.
+		p.id = id
+	}
+	if err != nil || !sent {
 		return err
 	}
 
-	_, ts, _, err := p.client.UpdateMessage(p.channel, p.ts, slack.MsgOptionText(msg, false))
-	p.ts = ts
-	p.lastPct = pct
-	return err
+	p.id = id
+	p.overdueNotify = false
+	p.lastSent = time.Now()
+	p.lastPos = msg.Pos
+	p.lastPct = msg.Pct
+	p.lastStep = p.step(msg.Pos, msg.Total)
+	p.resend = false
+	return nil
 }
 
-func (p *Progress) drawBar(pos int) string {
-	if pos == 0 {
-		return strings.Repeat(p.Opts.Empty, p.Opts.Width)
+// callHooks calls the hooks in Options after msg has been sent.
+func (p *Progress) callHooks(id string, first bool, msg *Message) {
+	if first && p.Opts.OnStart != nil {
+		p.Opts.OnStart(id, msg)
 	}
+	if !first && p.Opts.OnUpdate != nil {
+		p.Opts.OnUpdate(msg)
+	}
+	if msg.Complete && p.Opts.OnComplete != nil {
+		p.Opts.OnComplete(msg)
+	}
+}
+
+// deliver posts msg if this is the first message or updates the existing
+// message otherwise, retrying transient errors and waiting out rate limits.
+// sent is false if a rate limit made us skip an update that isn't final or
+// the sink returned ErrSkipped. A message that can't be edited is reposted
+// once. id may be set along with err if the sink posted the message in part.
+// p.sendMu must be held but not p.mu.
+func (p *Progress) deliver(ctx context.Context, msg *Message, final bool) (id string, sent bool, err error) {
+	id = p.id
+	reposted := false
+
+	for attempt := 1; ; attempt++ {
+		if err := ctx.Err(); err != nil {
+			return "", false, err
+		}
+		if wait := time.Until(p.notBefore); wait > 0 {
+			if err := sleep(ctx, wait); err != nil {
+				return "", false, err
+			}
+		}
+
+		// If there's no id this is the first time we've run so post a new
+		// message. History mode posts a new message every time.
+		if id == "" || p.Opts.History > 0 {
+			id, err = p.sink.Post(ctx, msg)
+		} else {
+			err = p.sink.Update(ctx, id, msg)
+		}
+		if err == nil {
+			return id, true, nil
+		}
+		if errors.Is(err, ErrSkipped) {
+			return id, false, nil // The sink only sends some updates
+		}
+
+		if id != "" && !reposted && errors.Is(err, ErrCantEdit) {
+			p.log(ctx, slog.LevelWarn, "Reposting message that can't be edited", "error", err)
+			id = ""
+			reposted = true
+			continue
+		}
+
+		var rl *RateLimitedError
+		if errors.As(err, &rl) {
+			p.notBefore = time.Now().Add(rl.RetryAfter)
+			if !final {
+				p.log(ctx, slog.LevelDebug, "Skipping rate limited update", "retry_after", rl.RetryAfter)
+				return "", false, nil // Try again with the next update
+			}
+			p.log(ctx, slog.LevelInfo, "Waiting for rate limit", "retry_after", rl.RetryAfter)
+			continue
+		}
 
-	bar := strings.Repeat(p.Opts.Fill, pos/p.Opts.Width)
-	bar += strings.Repeat(p.Opts.Empty, p.Opts.Width-len([]rune(bar)))
+		if attempt >= p.Opts.MaxAttempts || !transient(ctx, err) {
+			return id, false, err
+		}
+		backoff := p.backoff(attempt)
+		p.log(ctx, slog.LevelWarn, "Retrying message", "attempt", attempt, "backoff", backoff, "error", err)
+		if err := sleep(ctx, backoff); err != nil {
+			return "", false, err
+		}
+	}
+}
 
-	return bar
+// log logs msg through Options.Logger if one is set.
+func (p *Progress) log(ctx context.Context, level slog.Level, msg string, args ...any) {
+	if p.Opts.Logger == nil {
+		return
+	}
+	p.Opts.Logger.Log(ctx, level, msg, append([]any{"task", p.Opts.Task}, args...)...)
 }
 
-func (p *Progress) msg(pos int) (string, error) {
-	msg := &strings.Builder{}
+// render executes the tmpl template for msg.
+func (p *Progress) render(msg *Message, tmpl string) (string, error) {
+	text := &strings.Builder{}
 
 	data := map[string]interface{}{
-		"Task":        p.Opts.Task,
-		"ProgBar":     p.drawBar(pos),
-		"Pos":         pos,
-		"Remaining":   p.remaining(pos),
-		"Complete":    pos == 100,
-		"Elapsed":     time.Now().Sub(p.Start).Round(time.Millisecond),
+		"Task":        msg.Task,
+		"Phase":       msg.Phase,
+		"Status":      msg.Status,
+		"Log":         strings.Join(msg.Log, "\n"),
+		"ProgBar":     msg.Bar,
+		"Pos":         formatPercent(msg, p.Opts.Precision),
+		"Current":     msg.Pos,
+		"Total":       msg.Total,
+		"Unit":        p.Opts.Unit,
+		"Remaining":   p.duration(msg.Remaining),
+		"ETA":         p.formatETA(msg),
+		"StartTime":   p.Start,
+		"ETATime":     msg.ETA,
+		"EndTime":     endTime(p.Start, msg),
+		"Complete":    msg.Complete,
+		"Elapsed":     p.duration(msg.Elapsed),
+		"Rate":        msg.Rate,
+		"AvgRate":     msg.AvgRate,
+		"Failed":      msg.Failed,
+		"Counts":      msg.Counts,
+		"Err":         msg.Err,
+		"Stalled":     msg.Stalled,
+		"Overdue":     msg.Overdue,
+		"Paused":      msg.Paused,
 		"ShowEstTime": p.Opts.ShowEstTime,
 	}
+	// Leave zero alone so {{ if .Stalled }} keeps working
+	if msg.Stalled > 0 {
+		data["Stalled"] = p.duration(msg.Stalled)
+	}
+	if msg.Overdue > 0 {
+		data["Overdue"] = p.duration(msg.Overdue)
+	}
 
-	tmpl, err := template.New("msg").Parse(p.Opts.Msg)
+	t, err := p.template(tmpl)
 	if err != nil {
 		return "", err
 	}
-	err = tmpl.Execute(msg, data)
+	err = t.Execute(text, data)
+
+	return text.String(), err
+}
+
+// template returns the compiled template for src. Templates are compiled
+// once and reused so they aren't parsed on every update.
+func (p *Progress) template(src string) (*template.Template, error) {
+	if t, ok := p.templates[src]; ok {
+		return t, nil
+	}
+
+	t, err := template.New("msg").Funcs(funcs).Funcs(p.Opts.Funcs).Parse(src)
+	if err != nil {
+		return nil, err
+	}
+
+	p.templates[src] = t
+	return t, nil
+}
 
-	return msg.String(), err
+// rateWindow returns how far back the rate is measured.
+func (p *Progress) rateWindow() time.Duration {
+	if p.Opts.RateWindow > 0 {
+		return p.Opts.RateWindow
+	}
+	return DefaultRateWindow
 }
 
-// Calculate the remaining time
-func (p *Progress) remaining(pct int) time.Duration {
-	elapsed := time.Now().Sub(p.Start)
-	estTime := time.Duration(elapsed.Nanoseconds() / int64(pct) * int64(100))
-	remaining := estTime - elapsed
+// Calculate the remaining time. If sample is false a stateful estimator is
+// only asked for its estimate and doesn't learn from pos. p.mu must be held.
+func (p *Progress) remaining(pos, total int64, sample bool) time.Duration {
+	est := p.Opts.Estimator
+	if est == nil {
+		est = LinearEstimator{}
+	}
+
+	elapsed := p.elapsed(time.Now())
+	if s, ok := est.(statefulEstimator); ok && !sample {
+		return s.peek(pos, total, elapsed).Round(time.Second)
+	}
+	remaining := est.Estimate(pos, total, elapsed)
 	return remaining.Round(time.Second)
 }
 
-// New creates a new progress bar. If opts is nil then Progress will be created
-// with DefaultOptions. The timer that is used for calculating time remaining
-// is based on when this is instantiated so if it's not called around the time
-// the task begins running it might report inaccurate results. You can fix this
-// by setting Progress.Start manually.
-func New(token, channel string, opts *Options) *Progress {
+// New creates a new progress bar. Progress is created with DefaultOptions
+// customized by opts, which may also be a complete *Options. The timer that
+// is used for calculating time remaining is based on when this is
+// instantiated so if it's not called around the time the task begins running
+// it might report inaccurate results. You can fix this by setting
+// Progress.Start manually. If token is empty or Options.Terminal is set the
+// progress bar is drawn on the terminal instead of slack. An error is returned
+// if the options aren't valid, see Options.Validate.
+func New(token, channel string, opts ...Option) (*Progress, error) {
+	o := buildOptions(opts)
+	return NewWithSink(newSink(token, channel, o), o)
+}
+
+// newSink creates the sink used by New: the terminal if Options.Terminal is
+// set or token is empty and slack otherwise.
+func newSink(token, channel string, o *Options) Sink {
+	switch {
+	case o.Terminal != nil:
+		return NewTerminalSink(o.Terminal)
+	case token == "":
+		return NewTerminalSink(os.Stderr)
+	}
+	return NewSlackSink(token, channel, o)
+}
+
+// NewWithSink creates a new progress bar that delivers its messages to sink.
+// Progress is created with DefaultOptions customized by opts. An error is
+// returned if the options aren't valid, see Options.Validate.
+func NewWithSink(sink Sink, opts ...Option) (*Progress, error) {
 	progress := &Progress{
-		client:  slack.New(token),
-		channel: channel,
-		Start:   time.Now(),
-		Opts:    opts,
+		sink:      sink,
+		Start:     time.Now(),
+		moved:     time.Now(),
+		Opts:      buildOptions(opts),
+		aborted:   make(chan struct{}),
+		closed:    make(chan struct{}),
+		rearm:     make(chan struct{}, 1),
+		templates: map[string]*template.Template{},
+	}
+
+	if err := progress.Opts.Validate(); err != nil {
+		return nil, err
+	}
+	if progress.Opts.Theme != "" {
+		if err := applyTheme(progress.Opts); err != nil {
+			return nil, err
+		}
+	}
+
+	if _, ok := sink.(Deleter); progress.Opts.Cleanup == CleanupDelete && !ok {
+		return nil, ErrCantDelete
+	}
+
+	for _, src := range []string{progress.Opts.Msg, progress.Opts.FailMsg, progress.Opts.CompleteMsg} {
+		if _, err := progress.template(src); err != nil {
+			return nil, err
+		}
 	}
 
-	if opts == nil {
-		progress.Opts = DefaultOptions("Unknown Task")
+	progress.id = progress.Opts.MessageTS
+
+	if progress.Opts.Async {
+		progress.async = newSender()
+		go progress.sendLoop()
+	}
+	progress.startLoops()
+
+	if progress.Opts.Register {
+		register(progress)
 	}
+	return progress, nil
+}
 
-	return progress
+// startLoops starts the background goroutines for the options that need
+// them. They run until the task ends, Close is called or Reset starts a new
+// task. p.mu must be held or p must not be shared yet.
+func (p *Progress) startLoops() {
+	p.reset = make(chan struct{})
+	if p.Opts.Refresh > 0 {
+		go p.refreshLoop(p.reset)
+	}
+	if p.Opts.StallAfter > 0 {
+		go p.stallLoop(p.reset)
+	}
+	if !p.deadline(time.Now()).IsZero() {
+		go p.deadlineLoop(p.reset)
+	}
 }
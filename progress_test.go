@@ -1,11 +1,19 @@
 package progress_test
 
 import (
+	"context"
+	"errors"
 	"log"
 	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"text/template"
+	"time"
 
 	"github.com/sfreiberg/progress"
+	"github.com/sfreiberg/progress/progresstest"
 )
 
 func TestProgressBar(t *testing.T) {
@@ -15,10 +23,13 @@ func TestProgressBar(t *testing.T) {
 	)
 
 	if token == "" || channel == "" {
-		t.Fatalf("You must set the SLACK_TOKEN and SLACK_CHANNEL environment variables.")
+		t.Skip("Set the SLACK_TOKEN and SLACK_CHANNEL environment variables to post to slack.")
 	}
 
-	pbar := progress.New(token, channel, nil)
+	pbar, err := progress.New(token, channel, nil)
+	if err != nil {
+		t.Fatalf("Error creating progress bar: %s", err)
+	}
 
 	for i := 0; i <= pbar.Opts.TotalUnits; i++ {
 		if err := pbar.Update(i); err != nil {
@@ -26,3 +37,528 @@ func TestProgressBar(t *testing.T) {
 		}
 	}
 }
+
+// newProgress creates a progress bar that sends its messages to sink. Updates
+// aren't throttled so every change in percent is sent.
+func newProgress(t *testing.T, sink progress.Sink, opts ...progress.Option) *progress.Progress {
+	t.Helper()
+
+	pbar, err := progress.NewWithSink(sink, append(opts, progress.WithMinInterval(0))...)
+	if err != nil {
+		t.Fatalf("Error creating progress bar: %s", err)
+	}
+	return pbar
+}
+
+// memSink records every message it receives.
+type memSink struct {
+	posts   []*progress.Message
+	updates []*progress.Message
+}
+
+func (s *memSink) Post(ctx context.Context, msg *progress.Message) (string, error) {
+	s.posts = append(s.posts, msg)
+	return "1", nil
+}
+
+func (s *memSink) Update(ctx context.Context, id string, msg *progress.Message) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if id != "1" {
+		return errors.New("unknown message id " + id)
+	}
+	s.updates = append(s.updates, msg)
+	return nil
+}
+
+func TestSink(t *testing.T) {
+	sink := &memSink{}
+	pbar := newProgress(t, sink, nil)
+
+	for i := 0; i <= pbar.Opts.TotalUnits; i++ {
+		if err := pbar.Update(i); err != nil {
+			t.Fatalf("Error updating progress bar: %s", err)
+		}
+	}
+
+	if len(sink.posts) != 1 {
+		t.Fatalf("Expected 1 post, got %d", len(sink.posts))
+	}
+	if len(sink.updates) == 0 {
+		t.Fatalf("Expected updates, got none")
+	}
+
+	last := sink.updates[len(sink.updates)-1]
+	if !last.Complete || last.Pct != 100 {
+		t.Errorf("Expected last message to be complete, got %d%%", last.Pct)
+	}
+	if !strings.Contains(last.Text, "Completed in") {
+		t.Errorf("Expected completion text, got %q", last.Text)
+	}
+}
+
+func TestUpdateContext(t *testing.T) {
+	sink := &memSink{}
+	pbar := newProgress(t, sink, nil)
+
+	if err := pbar.Update(10); err != nil {
+		t.Fatalf("Error updating progress bar: %s", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if err := pbar.UpdateContext(ctx, 20); err != context.Canceled {
+		t.Fatalf("Expected %v, got %v", context.Canceled, err)
+	}
+
+	// The failed update shouldn't be remembered so it's sent again
+	if err := pbar.Update(20); err != nil {
+		t.Fatalf("Error updating progress bar: %s", err)
+	}
+	if len(sink.updates) != 1 || sink.updates[0].Pct != 20 {
+		t.Fatalf("Expected a single update to 20%%, got %d updates", len(sink.updates))
+	}
+}
+
+func TestConcurrentUpdates(t *testing.T) {
+	var (
+		sink  = &memSink{}
+		pbar  = newProgress(t, sink, nil)
+		count int64
+		wg    sync.WaitGroup
+	)
+
+	for w := 0; w < 8; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				pos := atomic.AddInt64(&count, 1)
+				if pos > int64(pbar.Opts.TotalUnits) {
+					return
+				}
+				if err := pbar.Update(int(pos)); err != nil {
+					t.Errorf("Error updating progress bar: %s", err)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	last := 0
+	for _, msg := range append(sink.posts, sink.updates...) {
+		if msg.Pct <= last {
+			t.Fatalf("Expected percent to increase, got %d after %d", msg.Pct, last)
+		}
+		last = msg.Pct
+	}
+}
+
+func TestOptions(t *testing.T) {
+	opts := progress.DefaultOptions("backup")
+	opts.Width = 20
+
+	pbar := newProgress(t, &memSink{}, opts, progress.WithFill("🟩"), progress.WithTotal(500))
+	if pbar.Opts != opts {
+		t.Fatalf("Expected Progress to use the Options that were passed in")
+	}
+	if opts.Task != "backup" || opts.Width != 20 || opts.Fill != "🟩" || opts.TotalUnits != 500 {
+		t.Errorf("Unexpected options %+v", opts)
+	}
+
+	pbar = newProgress(t, &memSink{}, progress.WithTask("deploy"), (*progress.Options)(nil))
+	if pbar.Opts.Task != "deploy" || pbar.Opts.Width != 10 {
+		t.Errorf("Unexpected options %+v", pbar.Opts)
+	}
+}
+
+func TestFinish(t *testing.T) {
+	sink := &memSink{}
+	pbar := newProgress(t, sink, nil)
+
+	if err := pbar.Update(30); err != nil {
+		t.Fatalf("Error updating progress bar: %s", err)
+	}
+	if err := pbar.Finish(); err != nil {
+		t.Fatalf("Error finishing progress bar: %s", err)
+	}
+
+	if len(sink.updates) != 1 || !sink.updates[0].Complete {
+		t.Fatalf("Expected a single complete update, got %d updates", len(sink.updates))
+	}
+}
+
+func TestFail(t *testing.T) {
+	sink := &memSink{}
+	pbar := newProgress(t, sink, nil)
+
+	if err := pbar.Update(40); err != nil {
+		t.Fatalf("Error updating progress bar: %s", err)
+	}
+	if err := pbar.Fail(errors.New("disk full")); err != nil {
+		t.Fatalf("Error failing progress bar: %s", err)
+	}
+	if err := pbar.Update(50); err != nil {
+		t.Fatalf("Error updating progress bar: %s", err)
+	}
+
+	if len(sink.updates) != 1 {
+		t.Fatalf("Expected updates after Fail to be ignored, got %d updates", len(sink.updates))
+	}
+
+	msg := sink.updates[0]
+	if !msg.Failed || msg.Pct != 40 {
+		t.Errorf("Expected failed message at 40%%, got %+v", msg)
+	}
+	if want := "`❌❌❌❌⬜⬜⬜⬜⬜⬜` 40%"; !strings.Contains(msg.Text, want) {
+		t.Errorf("Expected %q in %q", want, msg.Text)
+	}
+	if !strings.Contains(msg.Text, "disk full") {
+		t.Errorf("Expected error in %q", msg.Text)
+	}
+}
+
+func TestFailBeforeUpdate(t *testing.T) {
+	sink := &memSink{}
+	pbar := newProgress(t, sink, nil)
+
+	if err := pbar.Fail(errors.New("no such file")); err != nil {
+		t.Fatalf("Error failing progress bar: %s", err)
+	}
+	if len(sink.posts) != 1 || !sink.posts[0].Failed {
+		t.Fatalf("Expected a failed post, got %d posts", len(sink.posts))
+	}
+}
+
+func TestAdd(t *testing.T) {
+	var (
+		sink = &memSink{}
+		pbar = newProgress(t, sink, progress.WithTotal(1000))
+		wg   sync.WaitGroup
+	)
+
+	if err := pbar.Update(200); err != nil {
+		t.Fatalf("Error updating progress bar: %s", err)
+	}
+
+	for w := 0; w < 8; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 100; i++ {
+				if err := pbar.Increment(); err != nil {
+					t.Errorf("Error incrementing progress bar: %s", err)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	if err := pbar.Add(1); err != progress.ErrMaxPosExceeded {
+		t.Errorf("Expected %v, got %v", progress.ErrMaxPosExceeded, err)
+	}
+	// The failed Add mustn't leave the position out of range
+	if pos := pbar.Snapshot().Pos; pos != 1000 {
+		t.Errorf("Expected position 1000, got %d", pos)
+	}
+
+	last := sink.updates[len(sink.updates)-1]
+	if last.Pos != 1000 || !last.Complete {
+		t.Errorf("Expected to end at 1000, got %d", last.Pos)
+	}
+}
+
+func TestSetTotal(t *testing.T) {
+	sink := &memSink{}
+	pbar := newProgress(t, sink, nil)
+
+	if err := pbar.Update(50); err != nil {
+		t.Fatalf("Error updating progress bar: %s", err)
+	}
+	if err := pbar.SetTotal(0); err != progress.ErrInvalidTotal {
+		t.Fatalf("Expected %v, got %v", progress.ErrInvalidTotal, err)
+	}
+	if err := pbar.SetTotal(200); err != nil {
+		t.Fatalf("Error setting total: %s", err)
+	}
+	if err := pbar.Update(60); err != nil {
+		t.Fatalf("Error updating progress bar: %s", err)
+	}
+	if err := pbar.Update(150); err != nil {
+		t.Fatalf("Error updating progress bar: %s", err)
+	}
+
+	var pcts []int
+	for _, msg := range sink.updates {
+		pcts = append(pcts, msg.Pct)
+	}
+	if len(pcts) != 2 || pcts[0] != 30 || pcts[1] != 75 {
+		t.Errorf("Expected updates at 30%% and 75%%, got %v", pcts)
+	}
+}
+
+func TestUpdate64(t *testing.T) {
+	const gib = 1 << 30
+
+	sink := &memSink{}
+	pbar := newProgress(t, sink, progress.WithTotal64(8*gib))
+
+	for _, pos := range []int64{gib, 4 * gib, 8*gib - 1, 8 * gib} {
+		if err := pbar.Update64(pos); err != nil {
+			t.Fatalf("Error updating progress bar: %s", err)
+		}
+	}
+	if err := pbar.Update64(8*gib + 1); err != progress.ErrMaxPosExceeded {
+		t.Errorf("Expected %v, got %v", progress.ErrMaxPosExceeded, err)
+	}
+
+	var pcts []int
+	for _, msg := range append(sink.posts, sink.updates...) {
+		pcts = append(pcts, msg.Pct)
+	}
+	if len(pcts) != 4 || pcts[0] != 12 || pcts[1] != 50 || pcts[2] != 99 || pcts[3] != 100 {
+		t.Errorf("Expected 12%%, 50%%, 99%% and 100%%, got %v", pcts)
+	}
+}
+
+func TestFuncs(t *testing.T) {
+	sink := &memSink{}
+	pbar := newProgress(t, sink,
+		progress.WithTemplate("{{ shout .Task }} {{ comma .Pos }}"),
+		progress.WithTask("deploy"),
+		progress.WithFuncs(template.FuncMap{"shout": strings.ToUpper}),
+	)
+
+	if err := pbar.Update(42); err != nil {
+		t.Fatalf("Error updating progress bar: %s", err)
+	}
+	if got := sink.posts[0].Text; got != "DEPLOY 42" {
+		t.Errorf("Expected %q, got %q", "DEPLOY 42", got)
+	}
+}
+
+func TestBadTemplate(t *testing.T) {
+	if _, err := progress.NewWithSink(&memSink{}, progress.WithTemplate("{{ .Task ")); err == nil {
+		t.Errorf("Expected an error for an unparsable template")
+	}
+}
+
+func TestMinInterval(t *testing.T) {
+	sink := &memSink{}
+	pbar, err := progress.NewWithSink(sink, progress.WithMinInterval(time.Hour))
+	if err != nil {
+		t.Fatalf("Error creating progress bar: %s", err)
+	}
+
+	for i := 0; i <= pbar.Opts.TotalUnits; i++ {
+		if err := pbar.Update(i); err != nil {
+			t.Fatalf("Error updating progress bar: %s", err)
+		}
+	}
+
+	// Only the first and the final message get through
+	if len(sink.posts) != 1 || len(sink.updates) != 1 || !sink.updates[0].Complete {
+		t.Errorf("Expected 1 post and 1 final update, got %d posts and %d updates", len(sink.posts), len(sink.updates))
+	}
+}
+
+// rateLimitedSink rejects the first n updates with a RateLimitedError.
+type rateLimitedSink struct {
+	memSink
+	n int
+}
+
+func (s *rateLimitedSink) Update(ctx context.Context, id string, msg *progress.Message) error {
+	if s.n > 0 {
+		s.n--
+		return &progress.RateLimitedError{RetryAfter: 50 * time.Millisecond}
+	}
+	return s.memSink.Update(ctx, id, msg)
+}
+
+func TestRateLimited(t *testing.T) {
+	sink := &rateLimitedSink{n: 2}
+	pbar := newProgress(t, sink)
+
+	for _, pos := range []int{10, 20, 30} {
+		if err := pbar.Update(pos); err != nil {
+			t.Fatalf("Expected rate limited updates to be skipped, got %s", err)
+		}
+	}
+	if len(sink.updates) != 0 {
+		t.Fatalf("Expected no updates while rate limited, got %d", len(sink.updates))
+	}
+
+	// The final message waits until the rate limit has passed
+	start := time.Now()
+	if err := pbar.Finish(); err != nil {
+		t.Fatalf("Error finishing progress bar: %s", err)
+	}
+	if time.Since(start) < 40*time.Millisecond {
+		t.Errorf("Expected Finish to wait for the rate limit")
+	}
+	if len(sink.updates) != 1 || !sink.updates[0].Complete {
+		t.Errorf("Expected the final update, got %d updates", len(sink.updates))
+	}
+}
+
+func TestHooks(t *testing.T) {
+	var started, updated, completed int
+	var failed error
+	sink := &flakySink{errs: []error{errors.New("boom")}}
+	pbar := newProgress(t, sink, progress.WithHooks(
+		func(id string, msg *progress.Message) {
+			if id != "1" {
+				t.Errorf("Expected OnStart to get id 1, got %q", id)
+			}
+			started++
+		},
+		func(msg *progress.Message) { updated++ },
+		func(msg *progress.Message) {
+			if !msg.Complete {
+				t.Errorf("Expected OnComplete to get a complete message")
+			}
+			completed++
+		},
+		func(err error) { failed = err },
+	))
+
+	if err := pbar.Update(10); err == nil {
+		t.Fatalf("Expected the first post to fail")
+	}
+	if failed == nil || failed.Error() != "boom" {
+		t.Errorf("Expected OnError to get the post error, got %v", failed)
+	}
+
+	for _, pos := range []int{20, 30, 100} {
+		if err := pbar.Update(pos); err != nil {
+			t.Fatalf("Error updating progress bar: %s", err)
+		}
+	}
+	if started != 1 || updated != 2 || completed != 1 {
+		t.Errorf("Expected 1 start, 2 updates and 1 completion, got %d, %d and %d", started, updated, completed)
+	}
+}
+
+func TestMessageTS(t *testing.T) {
+	sink := &memSink{}
+	pbar := newProgress(t, sink, progress.WithMessageTS("1"))
+
+	if ts := pbar.MessageTS(); ts != "1" {
+		t.Errorf("Expected message ts 1, got %q", ts)
+	}
+	if err := pbar.Update(50); err != nil {
+		t.Fatalf("Error updating progress bar: %s", err)
+	}
+	if len(sink.posts) != 0 || len(sink.updates) != 1 {
+		t.Errorf("Expected the existing message to be edited, got %d posts and %d updates", len(sink.posts), len(sink.updates))
+	}
+}
+
+func TestCompleteMsg(t *testing.T) {
+	sink := &memSink{}
+	pbar := newProgress(t, sink, progress.WithTask("import"), progress.WithTotal(1500), progress.WithCompleteMsg(progress.DefaultCompleteMsg, false))
+
+	if err := pbar.Update(1500); err != nil {
+		t.Fatalf("Error updating progress bar: %s", err)
+	}
+	if text := sink.posts[0].Text; !strings.HasPrefix(text, "*import* completed in") || !strings.Contains(text, "Processed 1,500 of 1,500 at") {
+		t.Errorf("Expected the summary to replace the message, got %q", text)
+	}
+}
+
+func TestCompleteReply(t *testing.T) {
+	pbar, sink := progresstest.New(t, progress.WithTask("import"), progress.WithCompleteMsg("{{.Task}} done: {{.Current}} items", true))
+
+	for _, pos := range []int{50, 100} {
+		if err := pbar.Update(pos); err != nil {
+			t.Fatalf("Error updating progress bar: %s", err)
+		}
+	}
+	if last := sink.Last(); !strings.Contains(last.Text, "Completed in") {
+		t.Errorf("Expected the regular final message, got %q", last.Text)
+	}
+	if notes := sink.Notifications(); len(notes) != 1 || notes[0].Text != "import done: 100 items" {
+		t.Errorf("Expected the summary as a reply, got %+v", notes)
+	}
+}
+
+func TestUnit(t *testing.T) {
+	sink := &memSink{}
+	pbar := newProgress(t, sink, progress.WithTotal(10000), progress.WithUnit("rows"))
+
+	if err := pbar.Update(3214); err != nil {
+		t.Fatalf("Error updating progress bar: %s", err)
+	}
+	if text := sink.posts[0].Text; !strings.Contains(text, "32% (3,214 / 10,000 rows)") {
+		t.Errorf("Expected the position with its unit, got %q", text)
+	}
+}
+
+func TestPrecision(t *testing.T) {
+	sink := &memSink{}
+	pbar := newProgress(t, sink, progress.WithTotal(1000), progress.WithPrecision(1))
+
+	for _, pos := range []int{427, 428, 428} {
+		if err := pbar.Update(pos); err != nil {
+			t.Fatalf("Error updating progress bar: %s", err)
+		}
+	}
+
+	if len(sink.posts) != 1 || len(sink.updates) != 1 {
+		t.Fatalf("Expected an update for every tenth of a percent, got %d posts and %d updates", len(sink.posts), len(sink.updates))
+	}
+	if msg := sink.posts[0]; msg.Percent != 42.7 || !strings.Contains(msg.Text, "` 42.7%") {
+		t.Errorf("Expected 42.7%%, got %v in %q", msg.Percent, msg.Text)
+	}
+	if msg := sink.updates[0]; msg.Pct != 42 || !strings.Contains(msg.Text, "` 42.8%") {
+		t.Errorf("Expected 42.8%%, got %q", msg.Text)
+	}
+}
+
+func TestETA(t *testing.T) {
+	sink := &memSink{}
+	pbar := newProgress(t, sink, progress.WithTemplate("done {{.ETA}}"), progress.WithETA(time.RFC3339, nil))
+	pbar.Start = time.Now().Add(-time.Minute)
+
+	if err := pbar.Update(50); err != nil {
+		t.Fatalf("Error updating progress bar: %s", err)
+	}
+
+	msg := sink.posts[0]
+	if d := time.Until(msg.ETA); d < 59*time.Second || d > time.Minute {
+		t.Errorf("Expected the ETA in a minute, got %s", msg.ETA)
+	}
+	if want := "done ~" + msg.ETA.UTC().Format(time.RFC3339); msg.Text != want {
+		t.Errorf("Expected %q, got %q", want, msg.Text)
+	}
+}
+
+func TestAllowDecrease(t *testing.T) {
+	for _, allow := range []bool{false, true} {
+		opts := []progress.Option{}
+		if allow {
+			opts = append(opts, progress.WithAllowDecrease())
+		}
+		pbar, sink := progresstest.New(t, opts...)
+
+		for _, pos := range []int{60, 40} {
+			if err := pbar.Update(pos); err != nil {
+				t.Fatalf("Error updating progress bar: %s", err)
+			}
+		}
+		if err := pbar.Add(5); err != nil {
+			t.Fatalf("Error adding to progress bar: %s", err)
+		}
+
+		want := int64(65)
+		if allow {
+			want = 45
+		}
+		if last := sink.Last(); last.Pos != want {
+			t.Errorf("Expected position %d with AllowDecrease %v, got %d", want, allow, last.Pos)
+		}
+	}
+}
@@ -0,0 +1,57 @@
+// Package progressotel traces progress bars with OpenTelemetry, so the
+// messages sent to slack show up in the trace of the job they report on.
+package progressotel
+
+import (
+	"context"
+	"time"
+
+	"github.com/sfreiberg/progress"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Option creates a span with tracer for every message the progress bar sends.
+// Spans are children of the span in the context passed to UpdateContext and
+// friends, and are named progress.begin for the first message,
+// progress.complete and progress.fail for the last one and progress.update
+// for the others. They have the task, position, total and percent as
+// attributes, along with any attrs, e.g. the channel:
+//
+//	progressotel.Option(tracer, attribute.String("slack.channel", channel))
+//
+// The first message after Progress.Reset begins a new task, so its span is
+// named progress.begin again. It adds a hook to Options.OnSend, so it can be
+// combined with other send hooks. Use a new Option for every progress bar.
+func Option(tracer trace.Tracer, attrs ...attribute.KeyValue) progress.Option {
+	var start time.Time // Start of the task whose first message was sent
+
+	return progress.WithSendHook(func(ctx context.Context, msg *progress.Message, took time.Duration, err error) {
+		name := "progress.update"
+		switch {
+		case !msg.Start.Equal(start):
+			name = "progress.begin"
+		case msg.Failed:
+			name = "progress.fail"
+		case msg.Complete:
+			name = "progress.complete"
+		}
+		if err == nil {
+			start = msg.Start
+		}
+
+		end := time.Now()
+		_, span := tracer.Start(ctx, name, trace.WithTimestamp(end.Add(-took)), trace.WithAttributes(attrs...), trace.WithAttributes(
+			attribute.String("progress.task", msg.Task),
+			attribute.Int64("progress.pos", msg.Pos),
+			attribute.Int64("progress.total", msg.Total),
+			attribute.Float64("progress.percent", msg.Percent),
+		))
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+		}
+		span.End(trace.WithTimestamp(end))
+	})
+}
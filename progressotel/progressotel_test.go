@@ -0,0 +1,80 @@
+package progressotel_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/sfreiberg/progress"
+	"github.com/sfreiberg/progress/progressotel"
+	"github.com/sfreiberg/progress/progresstest"
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestOption(t *testing.T) {
+	rec := tracetest.NewSpanRecorder()
+	tracer := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(rec)).Tracer("test")
+
+	ctx, job := tracer.Start(context.Background(), "job")
+	pbar, _ := progresstest.New(t, progress.WithTask("import"), progressotel.Option(tracer, attribute.String("slack.channel", "C123")))
+	for _, pos := range []int{10, 50} {
+		if err := pbar.UpdateContext(ctx, pos); err != nil {
+			t.Fatalf("Error updating progress bar: %s", err)
+		}
+	}
+	if err := pbar.FinishContext(ctx); err != nil {
+		t.Fatalf("Error finishing progress bar: %s", err)
+	}
+	job.End()
+
+	spans := rec.Ended()
+	want := []string{"progress.begin", "progress.update", "progress.complete", "job"}
+	if len(spans) != len(want) {
+		t.Fatalf("Expected %d spans, got %d", len(want), len(spans))
+	}
+	for i, span := range spans {
+		if span.Name() != want[i] {
+			t.Errorf("Expected span %d to be %s, got %s", i, want[i], span.Name())
+		}
+	}
+
+	update := spans[1]
+	if update.Parent().SpanID() != job.SpanContext().SpanID() {
+		t.Error("Expected the span to be a child of the job")
+	}
+	attrs := map[attribute.Key]attribute.Value{}
+	for _, kv := range update.Attributes() {
+		attrs[kv.Key] = kv.Value
+	}
+	if attrs["progress.percent"].AsFloat64() != 50 || attrs["progress.task"].AsString() != "import" || attrs["slack.channel"].AsString() != "C123" {
+		t.Errorf("Unexpected attributes: %v", update.Attributes())
+	}
+}
+
+func TestOptionReset(t *testing.T) {
+	rec := tracetest.NewSpanRecorder()
+	tracer := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(rec)).Tracer("test")
+
+	pbar, _ := progresstest.New(t, progress.WithTask("build"), progressotel.Option(tracer))
+	if err := pbar.Update(100); err != nil {
+		t.Fatalf("Error updating progress bar: %s", err)
+	}
+	pbar.Reset("deploy")
+	for _, pos := range []int{50, 100} {
+		if err := pbar.Update(pos); err != nil {
+			t.Fatalf("Error updating progress bar: %s", err)
+		}
+	}
+
+	spans := rec.Ended()
+	want := []string{"progress.begin", "progress.begin", "progress.complete"}
+	if len(spans) != len(want) {
+		t.Fatalf("Expected %d spans, got %d", len(want), len(spans))
+	}
+	for i, span := range spans {
+		if span.Name() != want[i] {
+			t.Errorf("Expected span %d to be %s, got %s", i, want[i], span.Name())
+		}
+	}
+}
@@ -0,0 +1,85 @@
+// Package progressprom exports Prometheus metrics about progress bars, so
+// long running jobs can be watched in Grafana next to their slack message.
+package progressprom
+
+import (
+	"context"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/sfreiberg/progress"
+)
+
+// Collector is a prometheus.Collector with metrics about the progress bars
+// created with its Option. Every metric has a task label with the name of the
+// task:
+//
+//   - progress_percent: How far along the task is
+//   - progress_updates_total: Messages sent
+//   - progress_errors_total: Messages that couldn't be sent
+//   - progress_update_duration_seconds: How long sending a message took,
+//     including retries
+type Collector struct {
+	percent *prometheus.GaugeVec
+	updates *prometheus.CounterVec
+	errors  *prometheus.CounterVec
+	latency *prometheus.HistogramVec
+}
+
+// NewCollector creates a Collector. Register it with a prometheus.Registerer,
+// e.g. prometheus.MustRegister(c), and pass c.Option() to the progress bars.
+func NewCollector() *Collector {
+	labels := []string{"task"}
+	return &Collector{
+		percent: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "progress_percent",
+			Help: "How far along the task is in percent.",
+		}, labels),
+		updates: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "progress_updates_total",
+			Help: "Progress messages sent.",
+		}, labels),
+		errors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "progress_errors_total",
+			Help: "Progress messages that couldn't be sent.",
+		}, labels),
+		latency: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "progress_update_duration_seconds",
+			Help:    "How long sending a progress message took, including retries.",
+			Buckets: prometheus.DefBuckets,
+		}, labels),
+	}
+}
+
+// Option records the metrics of a progress bar. It adds a hook to
+// Options.OnSend, so it can be combined with other send hooks.
+func (c *Collector) Option() progress.Option {
+	return progress.WithSendHook(c.observe)
+}
+
+func (c *Collector) observe(ctx context.Context, msg *progress.Message, took time.Duration, err error) {
+	c.latency.WithLabelValues(msg.Task).Observe(took.Seconds())
+	if err != nil {
+		c.errors.WithLabelValues(msg.Task).Inc()
+		return
+	}
+
+	c.updates.WithLabelValues(msg.Task).Inc()
+	c.percent.WithLabelValues(msg.Task).Set(msg.Percent)
+}
+
+// Describe implements prometheus.Collector.
+func (c *Collector) Describe(ch chan<- *prometheus.Desc) {
+	c.percent.Describe(ch)
+	c.updates.Describe(ch)
+	c.errors.Describe(ch)
+	c.latency.Describe(ch)
+}
+
+// Collect implements prometheus.Collector.
+func (c *Collector) Collect(ch chan<- prometheus.Metric) {
+	c.percent.Collect(ch)
+	c.updates.Collect(ch)
+	c.errors.Collect(ch)
+	c.latency.Collect(ch)
+}
@@ -0,0 +1,69 @@
+package progressprom_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/sfreiberg/progress"
+	"github.com/sfreiberg/progress/progressotel"
+	"github.com/sfreiberg/progress/progressprom"
+	"github.com/sfreiberg/progress/progresstest"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestCollector(t *testing.T) {
+	c := progressprom.NewCollector()
+	pbar, sink := progresstest.New(t, progress.WithTask("import"), c.Option())
+
+	for _, pos := range []int{20, 40} {
+		if err := pbar.Update(pos); err != nil {
+			t.Fatalf("Error updating progress bar: %s", err)
+		}
+	}
+	sink.UpdateErr = errors.New("down")
+	pbar.Update(60)
+
+	want := `
+# HELP progress_errors_total Progress messages that couldn't be sent.
+# TYPE progress_errors_total counter
+progress_errors_total{task="import"} 1
+# HELP progress_percent How far along the task is in percent.
+# TYPE progress_percent gauge
+progress_percent{task="import"} 40
+# HELP progress_updates_total Progress messages sent.
+# TYPE progress_updates_total counter
+progress_updates_total{task="import"} 2
+`
+	if err := testutil.CollectAndCompare(c, strings.NewReader(want), "progress_percent", "progress_updates_total", "progress_errors_total"); err != nil {
+		t.Error(err)
+	}
+	if n := testutil.CollectAndCount(c, "progress_update_duration_seconds"); n != 1 {
+		t.Errorf("Expected a latency histogram, got %d", n)
+	}
+}
+
+func TestCollectorWithTracing(t *testing.T) {
+	rec := tracetest.NewSpanRecorder()
+	tracer := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(rec)).Tracer("test")
+
+	c := progressprom.NewCollector()
+	pbar, _ := progresstest.New(t, progress.WithTask("import"), c.Option(), progressotel.Option(tracer))
+	if err := pbar.Update(50); err != nil {
+		t.Fatalf("Error updating progress bar: %s", err)
+	}
+
+	want := `
+# HELP progress_updates_total Progress messages sent.
+# TYPE progress_updates_total counter
+progress_updates_total{task="import"} 1
+`
+	if err := testutil.CollectAndCompare(c, strings.NewReader(want), "progress_updates_total"); err != nil {
+		t.Error(err)
+	}
+	if n := len(rec.Ended()); n != 1 {
+		t.Errorf("Expected 1 span, got %d", n)
+	}
+}
@@ -0,0 +1,63 @@
+// Package progresssocket routes the button clicks on progress messages to
+// their callbacks through slack's Socket Mode, so interactive progress bars
+// work without exposing a public HTTP endpoint.
+package progresssocket
+
+import (
+	"context"
+
+	"github.com/sfreiberg/progress"
+	"github.com/slack-go/slack"
+	"github.com/slack-go/slack/socketmode"
+)
+
+// Run connects to slack with Socket Mode and passes the button clicks on
+// progress messages to progress.Dispatch until ctx is cancelled. appToken is
+// an app-level token (xapp-...) with the connections:write scope. Every
+// request is acknowledged, whether a progress bar handled it or not.
+func Run(ctx context.Context, appToken, botToken string) error {
+	client := socketmode.New(slack.New(botToken, slack.OptionAppLevelToken(appToken)))
+
+	go func() {
+		for {
+			select {
+			case evt := <-client.Events:
+				if !Handle(client, evt) {
+					ack(client, evt)
+				}
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return client.RunContext(ctx)
+}
+
+// Handle passes evt to progress.Dispatch if it's an interaction and
+// acknowledges it if a progress bar handled it. It returns whether one did so
+// the caller can handle the other events received by its own client. The
+// callbacks should return quickly because slack expects an acknowledgement
+// within three seconds.
+func Handle(client *socketmode.Client, evt socketmode.Event) bool {
+	if evt.Type != socketmode.EventTypeInteractive || evt.Request == nil {
+		return false
+	}
+	cb, ok := evt.Data.(slack.InteractionCallback)
+	if !ok || !progress.Dispatch(&cb) {
+		return false
+	}
+
+	client.Ack(*evt.Request)
+	return true
+}
+
+// ack acknowledges evt if slack expects it to be.
+func ack(client *socketmode.Client, evt socketmode.Event) {
+	switch evt.Type {
+	case socketmode.EventTypeInteractive, socketmode.EventTypeEventsAPI, socketmode.EventTypeSlashCommand:
+		if evt.Request != nil {
+			client.Ack(*evt.Request)
+		}
+	}
+}
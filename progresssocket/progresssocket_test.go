@@ -0,0 +1,46 @@
+package progresssocket_test
+
+import (
+	"testing"
+
+	"github.com/sfreiberg/progress"
+	"github.com/sfreiberg/progress/progresssocket"
+	"github.com/sfreiberg/progress/progresstest"
+	"github.com/slack-go/slack"
+	"github.com/slack-go/slack/socketmode"
+)
+
+func TestHandle(t *testing.T) {
+	var pressed *slack.InteractionCallback
+	pbar, _ := progresstest.New(t, progress.WithRegister(), progress.WithButton("socket_details", "Details", func(cb *slack.InteractionCallback) {
+		pressed = cb
+	}))
+	defer pbar.Close()
+	if err := pbar.Update(10); err != nil {
+		t.Fatalf("Error updating progress bar: %s", err)
+	}
+
+	client := socketmode.New(slack.New("xoxb-test"))
+	click := func(ts string) bool {
+		return progresssocket.Handle(client, socketmode.Event{
+			Type: socketmode.EventTypeInteractive,
+			Data: slack.InteractionCallback{
+				Type:           slack.InteractionTypeBlockActions,
+				User:           slack.User{ID: "U123"},
+				Container:      slack.Container{MessageTs: ts},
+				ActionCallback: slack.ActionCallbacks{BlockActions: []*slack.BlockAction{{ActionID: "socket_details"}}},
+			},
+			Request: &socketmode.Request{EnvelopeID: "envelope"},
+		})
+	}
+
+	if click("unknown") || pressed != nil {
+		t.Fatalf("Expected a click on another message to be ignored")
+	}
+	if !click(pbar.MessageTS()) {
+		t.Fatalf("Expected the click to be handled")
+	}
+	if pressed == nil || pressed.User.ID != "U123" {
+		t.Errorf("Expected the callback to be called with the interaction, got %v", pressed)
+	}
+}
@@ -0,0 +1,176 @@
+// Package progresstest provides a fake Sink for testing code that reports
+// progress without posting to a real slack workspace.
+package progresstest
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/sfreiberg/progress"
+)
+
+// Sink is a progress.Sink that records every message it receives. Each post
+// creates a new message with ids "1", "2" and so on. The zero value is ready
+// to use and it's safe for concurrent use.
+type Sink struct {
+	// PostErr and UpdateErr, if set, are returned by Post and Update instead
+	// of recording the message.
+	PostErr   error
+	UpdateErr error
+
+	mu            sync.Mutex
+	posts         []*progress.Message
+	updates       []*progress.Message
+	last          *progress.Message // The message posted or updated most recently
+	messages      map[string]*progress.Message
+	notifications []Notification
+	deleted       []string
+}
+
+// Notification is a notification sent with Sink.Notify.
+type Notification struct {
+	ID       string // The id of the message the notification is about
+	Text     string
+	Mentions []string
+}
+
+// New creates a progress bar that sends its messages to a new Sink. Updates
+// aren't throttled so every change in percent is recorded. The test fails if
+// the progress bar can't be created.
+func New(t testing.TB, opts ...progress.Option) (*progress.Progress, *Sink) {
+	t.Helper()
+
+	sink := &Sink{}
+	pbar, err := progress.NewWithSink(sink, append(opts, progress.WithMinInterval(0))...)
+	if err != nil {
+		t.Fatalf("Error creating progress bar: %s", err)
+	}
+	return pbar, sink
+}
+
+// Post records msg as a new message.
+func (s *Sink) Post(ctx context.Context, msg *progress.Message) (string, error) {
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.PostErr != nil {
+		return "", s.PostErr
+	}
+	if s.messages == nil {
+		s.messages = make(map[string]*progress.Message)
+	}
+
+	s.posts = append(s.posts, msg)
+	s.last = msg
+	id := strconv.Itoa(len(s.posts))
+	s.messages[id] = msg
+	return id, nil
+}
+
+// Update records msg as the new content of the message with the given id.
+func (s *Sink) Update(ctx context.Context, id string, msg *progress.Message) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.UpdateErr != nil {
+		return s.UpdateErr
+	}
+	if _, ok := s.messages[id]; !ok {
+		return fmt.Errorf("Unknown message id %q", id)
+	}
+
+	s.updates = append(s.updates, msg)
+	s.last = msg
+	s.messages[id] = msg
+	return nil
+}
+
+// Notify records a notification about the message with the given id.
+func (s *Sink) Notify(ctx context.Context, id, text string, mentions []string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.messages[id]; !ok {
+		return fmt.Errorf("Unknown message id %q", id)
+	}
+
+	s.notifications = append(s.notifications, Notification{ID: id, Text: text, Mentions: mentions})
+	return nil
+}
+
+// Delete removes the message with the given id.
+func (s *Sink) Delete(ctx context.Context, id string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.messages[id]; !ok {
+		return fmt.Errorf("Unknown message id %q", id)
+	}
+
+	delete(s.messages, id)
+	s.deleted = append(s.deleted, id)
+	return nil
+}
+
+// Deleted returns the ids of the messages that were deleted, in order.
+func (s *Sink) Deleted() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.deleted...)
+}
+
+// Notifications returns the notifications that were sent, in order.
+func (s *Sink) Notifications() []Notification {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Notification(nil), s.notifications...)
+}
+
+// Posts returns the messages that were posted, in order.
+func (s *Sink) Posts() []*progress.Message {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]*progress.Message(nil), s.posts...)
+}
+
+// Updates returns the messages that were edited in place, in order.
+func (s *Sink) Updates() []*progress.Message {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]*progress.Message(nil), s.updates...)
+}
+
+// Message returns the current content of the message with the given id or nil
+// if it wasn't posted.
+func (s *Sink) Message(id string) *progress.Message {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.messages[id]
+}
+
+// Last returns the last message that was posted or updated or nil if nothing
+// has been sent.
+func (s *Sink) Last() *progress.Message {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.last
+}
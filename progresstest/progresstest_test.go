@@ -0,0 +1,62 @@
+package progresstest_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/sfreiberg/progress"
+	"github.com/sfreiberg/progress/progresstest"
+)
+
+func TestSink(t *testing.T) {
+	pbar, sink := progresstest.New(t, progress.WithTask("deploy"), progress.WithWidth(10))
+
+	for _, pos := range []int{10, 50, 100} {
+		if err := pbar.Update(pos); err != nil {
+			t.Fatalf("Error updating progress bar: %s", err)
+		}
+	}
+
+	if len(sink.Posts()) != 1 || len(sink.Updates()) != 2 {
+		t.Fatalf("Expected 1 post and 2 updates, got %d and %d", len(sink.Posts()), len(sink.Updates()))
+	}
+
+	last := sink.Last()
+	if last != sink.Message("1") {
+		t.Errorf("Expected the last update to replace message 1")
+	}
+	if !last.Complete || !strings.Contains(last.Text, "deploy") {
+		t.Errorf("Expected a complete message for deploy, got %q", last.Text)
+	}
+}
+
+func TestSinkErrors(t *testing.T) {
+	pbar, sink := progresstest.New(t)
+	sink.PostErr = errors.New("boom")
+
+	if err := pbar.Update(10); err != sink.PostErr {
+		t.Fatalf("Expected PostErr, got %v", err)
+	}
+	if sink.Last() != nil {
+		t.Errorf("Expected nothing to be recorded")
+	}
+}
+
+func TestSinkLastAfterPost(t *testing.T) {
+	pbar, sink := progresstest.New(t, progress.WithTask("build"))
+
+	for _, pos := range []int{10, 50} {
+		if err := pbar.Update(pos); err != nil {
+			t.Fatalf("Error updating progress bar: %s", err)
+		}
+	}
+	pbar.Reset("deploy")
+	if err := pbar.Update(20); err != nil {
+		t.Fatalf("Error updating progress bar: %s", err)
+	}
+
+	if last := sink.Last(); last != sink.Message("2") || last.Task != "deploy" {
+		t.Errorf("Expected the last message to be the new post, got %+v", last)
+	}
+}
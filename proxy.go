@@ -0,0 +1,45 @@
+package progress
+
+import "io"
+
+// proxyReader advances a progress bar by the number of bytes read.
+type proxyReader struct {
+	r io.Reader
+	p *Progress
+}
+
+// NewProxyReader wraps r so every byte read from it advances the progress bar.
+// Set the total to the expected number of bytes (e.g. with SetTotal64) before
+// reading. Errors updating the progress bar don't interrupt reading.
+func (p *Progress) NewProxyReader(r io.Reader) io.Reader {
+	return &proxyReader{r: r, p: p}
+}
+
+func (pr *proxyReader) Read(b []byte) (int, error) {
+	n, err := pr.r.Read(b)
+	if n > 0 {
+		pr.p.Add64(int64(n))
+	}
+	return n, err
+}
+
+// proxyWriter advances a progress bar by the number of bytes written.
+type proxyWriter struct {
+	w io.Writer
+	p *Progress
+}
+
+// NewProxyWriter wraps w so every byte written to it advances the progress
+// bar. Set the total to the expected number of bytes (e.g. with SetTotal64)
+// before writing. Errors updating the progress bar don't interrupt writing.
+func (p *Progress) NewProxyWriter(w io.Writer) io.Writer {
+	return &proxyWriter{w: w, p: p}
+}
+
+func (pw *proxyWriter) Write(b []byte) (int, error) {
+	n, err := pw.w.Write(b)
+	if n > 0 {
+		pw.p.Add64(int64(n))
+	}
+	return n, err
+}
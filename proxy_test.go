@@ -0,0 +1,41 @@
+package progress_test
+
+import (
+	"bytes"
+	"io"
+	"testing"
+
+	"github.com/sfreiberg/progress"
+)
+
+func TestProxyReader(t *testing.T) {
+	sink := &memSink{}
+	pbar := newProgress(t, sink, progress.WithTotal(4096))
+
+	data := bytes.Repeat([]byte("x"), 4096)
+	n, err := io.Copy(io.Discard, pbar.NewProxyReader(bytes.NewReader(data)))
+	if err != nil || n != 4096 {
+		t.Fatalf("Expected to copy 4096 bytes, copied %d: %v", n, err)
+	}
+
+	msgs := append(sink.posts, sink.updates...)
+	if last := msgs[len(msgs)-1]; !last.Complete {
+		t.Errorf("Expected progress bar to be complete, got %d%%", last.Pct)
+	}
+}
+
+func TestProxyWriter(t *testing.T) {
+	sink := &memSink{}
+	pbar := newProgress(t, sink, progress.WithTotal(4096))
+
+	var buf bytes.Buffer
+	n, err := io.Copy(pbar.NewProxyWriter(&buf), bytes.NewReader(bytes.Repeat([]byte("x"), 2048)))
+	if err != nil || n != 2048 || buf.Len() != 2048 {
+		t.Fatalf("Expected to copy 2048 bytes, copied %d: %v", n, err)
+	}
+
+	msgs := append(sink.posts, sink.updates...)
+	if last := msgs[len(msgs)-1]; last.Pct != 50 {
+		t.Errorf("Expected progress bar at 50%%, got %d%%", last.Pct)
+	}
+}
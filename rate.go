@@ -0,0 +1,50 @@
+package progress
+
+import "time"
+
+// DefaultRateWindow is how far back the rate of progress is measured when
+// Options.RateWindow isn't set.
+const DefaultRateWindow = 10 * time.Second
+
+// sample is a position recorded at a point in time.
+type sample struct {
+	t   time.Time
+	pos int64
+}
+
+// rateWindow keeps recent samples to calculate the rate of progress over a
+// sliding window.
+type rateWindow struct {
+	samples []sample
+}
+
+// add records pos at time t. Samples that are closer together than a
+// hundredth of the window are dropped to keep memory bounded in hot loops.
+func (rw *rateWindow) add(t time.Time, pos int64, window time.Duration) {
+	if n := len(rw.samples); n > 0 && t.Sub(rw.samples[n-1].t) < window/100 {
+		return
+	}
+	rw.samples = append(rw.samples, sample{t: t, pos: pos})
+
+	// Keep a single sample older than the window to measure from
+	drop := 0
+	for drop < len(rw.samples)-1 && t.Sub(rw.samples[drop+1].t) >= window {
+		drop++
+	}
+	rw.samples = append(rw.samples[:0], rw.samples[drop:]...)
+}
+
+// rate returns the units per second between the oldest sample in the window
+// and pos at time t.
+func (rw *rateWindow) rate(t time.Time, pos int64) float64 {
+	if len(rw.samples) == 0 {
+		return 0
+	}
+
+	first := rw.samples[0]
+	dt := t.Sub(first.t).Seconds()
+	if dt <= 0 || pos < first.pos {
+		return 0
+	}
+	return float64(pos-first.pos) / dt
+}
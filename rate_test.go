@@ -0,0 +1,33 @@
+package progress
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRateWindow(t *testing.T) {
+	var (
+		rw     rateWindow
+		start  = time.Now()
+		window = 10 * time.Second
+	)
+
+	// 100 units per second for 20 seconds, then 10 units per second
+	pos := int64(0)
+	for s := 1; s <= 30; s++ {
+		if s <= 20 {
+			pos += 100
+		} else {
+			pos += 10
+		}
+		rw.add(start.Add(time.Duration(s)*time.Second), pos, window)
+	}
+
+	now := start.Add(30 * time.Second)
+	if got := rw.rate(now, pos); got < 9 || got > 20 {
+		t.Errorf("Expected rate close to 10/s, got %f", got)
+	}
+	if len(rw.samples) > 12 {
+		t.Errorf("Expected old samples to be dropped, have %d", len(rw.samples))
+	}
+}
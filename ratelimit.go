@@ -0,0 +1,31 @@
+package progress
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// retryAfter parses the Retry-After header of a 429 response. Slack and most
+// other chat systems send the number of seconds to wait.
+func retryAfter(header http.Header) time.Duration {
+	secs, err := strconv.Atoi(header.Get("Retry-After"))
+	if err != nil || secs < 0 {
+		return time.Second
+	}
+	return time.Duration(secs) * time.Second
+}
+
+// sleep waits for d or until ctx is done.
+func sleep(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+
+	select {
+	case <-t.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,53 @@
+package progress
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/slack-go/slack"
+)
+
+// Reactions added to the progress message when Options.Reactions is set.
+const (
+	ReactionStarted  = "hourglass_flowing_sand" // ⏳ while the task is running
+	ReactionComplete = "white_check_mark"       // ✅ once the task has completed
+	ReactionFailed   = "x"                      // ❌ if the task failed
+)
+
+// react adds the reaction for the state of msg to the message ts and removes
+// the previous one. Reactions are decoration so errors, such as a missing
+// reactions:write scope, are logged instead of failing the update.
+func (s *slackSink) react(ctx context.Context, ts string, msg *Message) {
+	if !s.opts.Reactions {
+		return
+	}
+
+	name := ReactionStarted
+	switch {
+	case msg.Failed:
+		name = ReactionFailed
+	case msg.Complete:
+		name = ReactionComplete
+	}
+	if name == s.reaction {
+		return
+	}
+
+	ref := slack.NewRefToMessage(s.channel, ts)
+	if err := s.client.AddReactionContext(ctx, name, ref); err != nil {
+		s.logReactionError(ctx, name, err)
+		return
+	}
+	if s.reaction != "" {
+		if err := s.client.RemoveReactionContext(ctx, s.reaction, ref); err != nil {
+			s.logReactionError(ctx, s.reaction, err)
+		}
+	}
+	s.reaction = name
+}
+
+func (s *slackSink) logReactionError(ctx context.Context, name string, err error) {
+	if s.opts.Logger != nil {
+		s.opts.Logger.Log(ctx, slog.LevelWarn, "Error changing reaction", "task", s.opts.Task, "reaction", name, "error", err)
+	}
+}
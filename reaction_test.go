@@ -0,0 +1,46 @@
+package progress
+
+import (
+	"net/url"
+	"testing"
+)
+
+func TestReactions(t *testing.T) {
+	var calls []string
+	opts := DefaultOptions("deploy")
+	opts.Reactions = true
+	sink, done := newTestSlackSink(t, opts, func(method string, form url.Values) string {
+		switch method {
+		case "reactions.add", "reactions.remove":
+			if form.Get("channel") != "C123" || form.Get("timestamp") != "1.2" {
+				t.Errorf("Expected a reaction to C123 1.2, got %v", form)
+			}
+			calls = append(calls, method+" "+form.Get("name"))
+			return `{"ok":true}`
+		default:
+			return `{"ok":true,"channel":"C123","ts":"1.2"}`
+		}
+	})
+	defer done()
+
+	pbar, err := NewWithSink(sink, opts, WithMinInterval(0))
+	if err != nil {
+		t.Fatalf("Error creating progress bar: %s", err)
+	}
+	for _, pos := range []int{25, 50, 100} {
+		if err := pbar.Update(pos); err != nil {
+			t.Fatalf("Error updating progress bar: %s", err)
+		}
+	}
+
+	want := []string{"reactions.add hourglass_flowing_sand", "reactions.add white_check_mark", "reactions.remove hourglass_flowing_sand"}
+	if len(calls) != len(want) {
+		t.Fatalf("Expected %v, got %v", want, calls)
+	}
+	for i := range want {
+		if calls[i] != want[i] {
+			t.Errorf("Expected %v, got %v", want, calls)
+			break
+		}
+	}
+}
@@ -0,0 +1,49 @@
+package progress
+
+import (
+	"context"
+	"time"
+)
+
+// refreshLoop re-sends the latest position every Options.Refresh until the
+// task has ended or Close or Reset is called.
+func (p *Progress) refreshLoop(reset <-chan struct{}) {
+	ticker := time.NewTicker(p.Opts.Refresh)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+		case <-p.closed:
+			return
+		case <-reset:
+			return
+		}
+
+		if !p.refresh() {
+			return
+		}
+	}
+}
+
+// refresh re-renders the message with the latest position unless a message
+// was sent within the last Options.Refresh anyway. It returns false once the
+// task has ended.
+func (p *Progress) refresh() bool {
+	p.sendMu.Lock()
+	defer p.sendMu.Unlock()
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.done || p.lastPct == 100 {
+		return false
+	}
+	if p.id == "" || time.Since(p.lastSent) < p.Opts.Refresh {
+		return true // Nothing to refresh yet
+	}
+
+	// Errors are logged and passed to Options.OnError by send
+	p.resend = true
+	p.update(context.Background(), p.count.Load(), p.total())
+	return true
+}
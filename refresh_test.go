@@ -0,0 +1,40 @@
+package progress_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/sfreiberg/progress"
+	"github.com/sfreiberg/progress/progresstest"
+)
+
+func TestRefresh(t *testing.T) {
+	pbar, sink := progresstest.New(t, progress.WithRefresh(10*time.Millisecond))
+	defer pbar.Close()
+
+	if err := pbar.Update(50); err != nil {
+		t.Fatalf("Error updating progress bar: %s", err)
+	}
+
+	deadline := time.Now().Add(time.Second)
+	for len(sink.Updates()) < 2 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	updates := sink.Updates()
+	if len(updates) < 2 {
+		t.Fatalf("Expected the message to be refreshed, got %d updates", len(updates))
+	}
+	if updates[1].Pct != 50 || updates[1].Elapsed <= sink.Posts()[0].Elapsed {
+		t.Errorf("Expected the same percent with a later elapsed time, got %d%% after %s", updates[1].Pct, updates[1].Elapsed)
+	}
+
+	// Refreshing stops once the task is complete
+	if err := pbar.Finish(); err != nil {
+		t.Fatalf("Error finishing progress bar: %s", err)
+	}
+	n := len(sink.Updates())
+	time.Sleep(50 * time.Millisecond)
+	if len(sink.Updates()) != n {
+		t.Errorf("Expected no refreshes after the task completed")
+	}
+}
@@ -0,0 +1,163 @@
+package progress
+
+import (
+	"encoding/json"
+	"math"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// running are the progress bars whose task hasn't completed or failed yet in
+// the order they were created.
+var running struct {
+	sync.Mutex
+	bars []*Progress
+}
+
+// register adds p to the running progress bars.
+func register(p *Progress) {
+	running.Lock()
+	defer running.Unlock()
+	running.bars = append(running.bars, p)
+}
+
+// unregister removes p from the running progress bars. It's safe to call it
+// more than once.
+func unregister(p *Progress) {
+	running.Lock()
+	defer running.Unlock()
+	for i, bar := range running.bars {
+		if bar == p {
+			running.bars = append(running.bars[:i], running.bars[i+1:]...)
+			return
+		}
+	}
+}
+
+// Running returns the progress bars in this process that were created with
+// Options.Register and are still running, oldest first. A progress bar stops
+// running once its task completes or fails or when Close is called. Child
+// progress bars aren't included, their parent is.
+func Running() []*Progress {
+	running.Lock()
+	defer running.Unlock()
+	return append([]*Progress(nil), running.bars...)
+}
+
+// Snapshot is the state of a progress bar at one point in time.
+type Snapshot struct {
+	Task      string
+	Phase     string        // The part of the task that's running
+	Status    string        // Set by Progress.SetStatus
+	Pos       int64         // The highest position passed to Update or Add
+	Total     int64         // Total possible units
+	Pct       int           // Percent complete
+	Bar       string        // The progress bar drawn by the Renderer
+	Start     time.Time     // When the task began running
+	Elapsed   time.Duration // Time since the task began running
+	Remaining time.Duration // Estimated time remaining
+	ETA       time.Time     // Estimated completion time. Zero if there's no estimate.
+	Stalled   bool          // Whether no progress has been made for Options.StallAfter
+	Paused    bool          // Whether the task is paused, see Progress.Pause
+	MessageTS string        // The id of the message, see Progress.MessageTS
+	Permalink string        // The link to the message, see Progress.Permalink
+}
+
+// Snapshot returns the current state of the progress bar, including progress
+// that hasn't been sent yet.
+func (p *Progress) Snapshot() Snapshot {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	now := time.Now()
+	pos := p.count.Load()
+	total := p.total()
+	s := Snapshot{
+		Task:      p.Opts.Task,
+		Phase:     p.phase,
+		Status:    p.status,
+		Pos:       pos,
+		Total:     total,
+		Pct:       percent(pos, total),
+		Start:     p.Start,
+		Elapsed:   p.elapsed(now).Round(time.Millisecond),
+		Remaining: p.remaining(pos, total, false),
+		Stalled:   p.Opts.StallAfter > 0 && now.Sub(p.moved) >= p.Opts.StallAfter && pos < total && p.paused.IsZero(),
+		Paused:    !p.paused.IsZero(),
+		MessageTS: p.id,
+		Permalink: p.permalink,
+	}
+	if s.Remaining > 0 {
+		s.ETA = now.Add(s.Remaining)
+	}
+	deadline := p.deadline(now)
+	s.Bar = p.renderer().Render(State{
+		Pos:      pos,
+		Total:    total,
+		Pct:      s.Pct,
+		Percent:  float64(p.step(pos, total)) / math.Pow10(p.Opts.Precision),
+		Width:    p.Opts.Width,
+		Complete: pos == total,
+		Overdue:  !deadline.IsZero() && now.After(deadline),
+		Counts:   p.counts(),
+		Segments: p.segments,
+	})
+	return s
+}
+
+// snapshotJSON is a Snapshot served by Handler.
+type snapshotJSON struct {
+	Task      string     `json:"task"`
+	Phase     string     `json:"phase,omitempty"`
+	Status    string     `json:"status,omitempty"`
+	Pos       int64      `json:"pos"`
+	Total     int64      `json:"total"`
+	Pct       int        `json:"pct"`
+	Start     time.Time  `json:"start"`
+	Elapsed   float64    `json:"elapsed"`             // Seconds
+	Remaining float64    `json:"remaining,omitempty"` // Seconds
+	ETA       *time.Time `json:"eta,omitempty"`
+	Stalled   bool       `json:"stalled,omitempty"`
+	Paused    bool       `json:"paused,omitempty"`
+	MessageTS string     `json:"message_ts,omitempty"`
+	Permalink string     `json:"permalink,omitempty"`
+}
+
+// Handler creates an http.Handler that serves the snapshots of the Running
+// progress bars as a JSON array so health dashboards can show what the
+// process is working on, e.g.
+//
+//	http.Handle("/debug/progress", progress.Handler())
+func Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		bars := Running()
+		out := make([]snapshotJSON, len(bars))
+		for i, bar := range bars {
+			s := bar.Snapshot()
+			out[i] = snapshotJSON{
+				Task:      s.Task,
+				Phase:     s.Phase,
+				Status:    s.Status,
+				Pos:       s.Pos,
+				Total:     s.Total,
+				Pct:       s.Pct,
+				Start:     s.Start,
+				Elapsed:   s.Elapsed.Seconds(),
+				Remaining: s.Remaining.Seconds(),
+				Stalled:   s.Stalled,
+				Paused:    s.Paused,
+				MessageTS: s.MessageTS,
+				Permalink: s.Permalink,
+			}
+			if !s.ETA.IsZero() {
+				out[i].ETA = &s.ETA
+			}
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(out); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+	})
+}
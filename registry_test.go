@@ -0,0 +1,56 @@
+package progress_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+
+	"github.com/sfreiberg/progress"
+)
+
+func TestHandler(t *testing.T) {
+	pbar := newProgress(t, &memSink{}, progress.WithTask("handler import"), progress.WithRegister())
+	closed := newProgress(t, &memSink{}, progress.WithTask("handler closed"), progress.WithRegister())
+	if err := pbar.Update(25); err != nil {
+		t.Fatalf("Error updating progress bar: %s", err)
+	}
+	closed.Close()
+
+	if slices.Contains(progress.Running(), closed) {
+		t.Errorf("Expected closed progress bar not to be running")
+	}
+
+	w := httptest.NewRecorder()
+	progress.Handler().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+	if ct := w.Header().Get("Content-Type"); ct != "application/json" {
+		t.Errorf("Expected JSON, got %q", ct)
+	}
+
+	var bars []map[string]interface{}
+	if err := json.NewDecoder(w.Body).Decode(&bars); err != nil {
+		t.Fatalf("Error decoding response: %s", err)
+	}
+	i := slices.IndexFunc(bars, func(bar map[string]interface{}) bool { return bar["task"] == "handler import" })
+	if i < 0 {
+		t.Fatalf("Expected progress bar in %v", bars)
+	}
+	if bars[i]["pct"] != 25.0 || bars[i]["message_ts"] != "1" {
+		t.Errorf("Unexpected progress bar: %v", bars[i])
+	}
+
+	if err := pbar.Finish(); err != nil {
+		t.Fatalf("Error finishing progress bar: %s", err)
+	}
+	if slices.Contains(progress.Running(), pbar) {
+		t.Errorf("Expected completed progress bar not to be running")
+	}
+}
+
+func TestRunningIsOptIn(t *testing.T) {
+	pbar := newProgress(t, &memSink{}, progress.WithTask("unregistered"))
+	if slices.Contains(progress.Running(), pbar) {
+		t.Errorf("Expected progress bar without WithRegister not to be running")
+	}
+}
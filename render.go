@@ -0,0 +1,32 @@
+package progress
+
+// RenderToString renders the message for the current state of the progress
+// bar without sending it, e.g. for golden file tests, to preview templates or
+// to show the same message somewhere else.
+func (p *Progress) RenderToString() (string, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	var msg *Message
+	var tmpl string
+	if p.done {
+		msg, tmpl = p.failMessage(p.err), p.Opts.FailMsg
+	} else {
+		msg, tmpl = p.message(p.count.Load(), p.total(), false)
+	}
+
+	if err := p.renderFit(msg, tmpl); err != nil {
+		return "", err
+	}
+	return msg.Text, nil
+}
+
+// String returns the message for the current state of the progress bar, or
+// the error if it can't be rendered. See RenderToString.
+func (p *Progress) String() string {
+	text, err := p.RenderToString()
+	if err != nil {
+		return err.Error()
+	}
+	return text
+}
@@ -0,0 +1,40 @@
+package progress_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/sfreiberg/progress"
+	"github.com/sfreiberg/progress/progresstest"
+)
+
+func TestRenderToString(t *testing.T) {
+	pbar, sink := progresstest.New(t, progress.WithTask("import"), progress.WithTemplate("{{.Task}} `{{.ProgBar}}` {{.Pos}}%"),
+		// The elapsed time may change between sending and rendering again
+		progress.WithFailure("❌", "{{.Task}} `{{.ProgBar}}` failed: {{.Err}}"))
+
+	text, err := pbar.RenderToString()
+	if err != nil {
+		t.Fatalf("Error rendering: %s", err)
+	}
+	if want := "import `⬜⬜⬜⬜⬜⬜⬜⬜⬜⬜` 0%"; text != want {
+		t.Errorf("Expected %q, got %q", want, text)
+	}
+	if len(sink.Posts()) != 0 {
+		t.Errorf("Expected nothing to be sent, got %d posts", len(sink.Posts()))
+	}
+
+	if err := pbar.Update(40); err != nil {
+		t.Fatalf("Error updating progress bar: %s", err)
+	}
+	if text := pbar.String(); text != sink.Last().Text {
+		t.Errorf("Expected the message that was sent, %q, got %q", sink.Last().Text, text)
+	}
+
+	if err := pbar.Fail(errors.New("disk full")); err != nil {
+		t.Fatalf("Error failing progress bar: %s", err)
+	}
+	if text := pbar.String(); text != sink.Last().Text {
+		t.Errorf("Expected the failure message, %q, got %q", sink.Last().Text, text)
+	}
+}
@@ -0,0 +1,38 @@
+package progress_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/sfreiberg/progress"
+	"github.com/sfreiberg/progress/progresstest"
+)
+
+func TestRenderer(t *testing.T) {
+	var states []progress.State
+	numbers := progress.RendererFunc(func(s progress.State) string {
+		states = append(states, s)
+		if s.Failed {
+			return "failed"
+		}
+		return fmt.Sprintf("%d/%d", s.Pos, s.Total)
+	})
+	pbar, sink := progresstest.New(t, progress.WithRenderer(numbers), progress.WithWidth(20))
+
+	if err := pbar.Update(40); err != nil {
+		t.Fatalf("Error updating progress bar: %s", err)
+	}
+	if bar := sink.Last().Bar; bar != "40/100" {
+		t.Errorf("Expected the bar from the renderer, got %q", bar)
+	}
+	if err := pbar.Fail(fmt.Errorf("disk full")); err != nil {
+		t.Fatalf("Error failing progress bar: %s", err)
+	}
+	if bar := sink.Last().Bar; bar != "failed" {
+		t.Errorf("Expected the failed bar from the renderer, got %q", bar)
+	}
+
+	if len(states) != 2 || states[0].Pct != 40 || states[0].Width != 20 || !states[1].Failed {
+		t.Errorf("Unexpected states: %+v", states)
+	}
+}
@@ -0,0 +1,63 @@
+package progress
+
+import "time"
+
+// Reset starts a new task named task with the same Progress so sequential
+// tasks can share one sink, and slack connection, instead of creating a new
+// progress bar for each. The position, counts, status, log, phase, children,
+// timestamps and what the Estimator has learned are cleared, Start is set to
+// now and the next update posts a new message. The options, the Aborted
+// channel and any pending cleanup of the previous message are kept.
+func (p *Progress) Reset(task string) {
+	p.sendMu.Lock()
+	defer p.sendMu.Unlock()
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	close(p.reset)
+
+	now := time.Now()
+	p.Opts.Task = task
+	p.Start = now
+	p.count.Store(0)
+	for i := range p.tally {
+		p.tally[i].Store(0)
+	}
+	p.id = ""
+	p.lastPos = 0
+	p.lastPct = 0
+	p.lastStep = 0
+	p.resend = false
+	p.rates = rateWindow{}
+	if est, ok := p.Opts.Estimator.(statefulEstimator); ok {
+		est.reset()
+	}
+	p.pending = 0
+
+	p.phase = ""
+	p.segments = nil
+	p.status = ""
+	p.logTail = nil
+	p.moved = now
+	p.movedPos = 0
+	p.stalled = false
+	p.overdueNotify = false
+	p.children = nil
+
+	p.lastSent = time.Time{}
+	p.paused = time.Time{}
+	p.pausedFor = 0
+	p.permalink = ""
+	p.done = false
+	p.err = nil
+
+	p.startLoops()
+	unregister(p)
+	select {
+	case <-p.closed: // Closed progress bars aren't running
+	default:
+		if p.Opts.Register {
+			register(p)
+		}
+	}
+}
@@ -0,0 +1,39 @@
+package progress_test
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/sfreiberg/progress"
+	"github.com/sfreiberg/progress/progresstest"
+)
+
+func TestReset(t *testing.T) {
+	pbar, sink := progresstest.New(t, progress.WithTask("extract"), progress.WithRegister())
+	defer pbar.Close()
+	if err := pbar.SetStatus("reading files"); err != nil {
+		t.Fatalf("Error setting status: %s", err)
+	}
+	if err := pbar.Finish(); err != nil {
+		t.Fatalf("Error finishing progress bar: %s", err)
+	}
+
+	pbar.Reset("load")
+	if pbar.MessageTS() != "" {
+		t.Errorf("Expected no message after reset, got %q", pbar.MessageTS())
+	}
+	if !slices.Contains(progress.Running(), pbar) {
+		t.Errorf("Expected the new task to be running")
+	}
+
+	if err := pbar.Update(30); err != nil {
+		t.Fatalf("Error updating progress bar: %s", err)
+	}
+	if len(sink.Posts()) != 2 {
+		t.Fatalf("Expected a new message for the new task, got %d posts", len(sink.Posts()))
+	}
+	msg := sink.Posts()[1]
+	if msg.Task != "load" || msg.Pos != 30 || msg.Status != "" || msg.Complete {
+		t.Errorf("Unexpected message for the new task: %+v", msg)
+	}
+}
@@ -0,0 +1,45 @@
+package progress
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"net"
+	"time"
+)
+
+// transient reports whether err is worth retrying. Network errors and 5xx
+// responses usually go away on their own, everything else won't.
+func transient(ctx context.Context, err error) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+
+	var se *StatusError
+	if errors.As(err, &se) {
+		return se.Code >= 500
+	}
+
+	var ne net.Error
+	return errors.As(err, &ne)
+}
+
+// backoff returns how long to wait before retry number attempt (starting at
+// 1). The wait doubles with every attempt, up to MaxRetryBackoff if it's
+// greater than 0, and is randomized between half and the full amount so
+// concurrent tasks don't retry in lockstep.
+func (p *Progress) backoff(attempt int) time.Duration {
+	d := p.Opts.RetryBackoff
+	capped := p.Opts.MaxRetryBackoff > 0
+	for i := 1; i < attempt && (!capped || d < p.Opts.MaxRetryBackoff); i++ {
+		d *= 2
+	}
+	if capped && d > p.Opts.MaxRetryBackoff {
+		d = p.Opts.MaxRetryBackoff
+	}
+	if d <= 0 {
+		return 0
+	}
+
+	return d/2 + time.Duration(rand.Int63n(int64(d/2)+1))
+}
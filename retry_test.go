@@ -0,0 +1,144 @@
+package progress_test
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sfreiberg/progress"
+	"github.com/sfreiberg/progress/progresstest"
+)
+
+// flakySink fails the first len(errs) posts with the given errors.
+type flakySink struct {
+	memSink
+	errs  []error
+	calls int
+}
+
+func (s *flakySink) Post(ctx context.Context, msg *progress.Message) (string, error) {
+	s.calls++
+	if len(s.errs) > 0 {
+		err := s.errs[0]
+		s.errs = s.errs[1:]
+		return "", err
+	}
+	return s.memSink.Post(ctx, msg)
+}
+
+func TestRetry(t *testing.T) {
+	unavailable := &progress.StatusError{Code: http.StatusServiceUnavailable, Status: "503 Service Unavailable"}
+	sink := &flakySink{errs: []error{unavailable, unavailable}}
+	pbar := newProgress(t, sink, progress.WithRetry(3, time.Millisecond, 5*time.Millisecond))
+
+	if err := pbar.Update(10); err != nil {
+		t.Fatalf("Expected transient errors to be retried, got %s", err)
+	}
+	if sink.calls != 3 || len(sink.posts) != 1 {
+		t.Errorf("Expected 3 attempts and 1 post, got %d attempts and %d posts", sink.calls, len(sink.posts))
+	}
+}
+
+func TestRetryGivesUp(t *testing.T) {
+	unavailable := &progress.StatusError{Code: http.StatusBadGateway, Status: "502 Bad Gateway"}
+	sink := &flakySink{errs: []error{unavailable, unavailable, unavailable}}
+	pbar := newProgress(t, sink, progress.WithRetry(2, time.Millisecond, 5*time.Millisecond))
+
+	var se *progress.StatusError
+	if err := pbar.Update(10); !errors.As(err, &se) {
+		t.Fatalf("Expected a StatusError, got %v", err)
+	}
+	if sink.calls != 2 {
+		t.Errorf("Expected 2 attempts, got %d", sink.calls)
+	}
+}
+
+func TestRetryPermanentError(t *testing.T) {
+	sink := &flakySink{errs: []error{&progress.StatusError{Code: http.StatusNotFound, Status: "404 Not Found"}}}
+	pbar := newProgress(t, sink, progress.WithRetry(3, time.Millisecond, 5*time.Millisecond))
+
+	if err := pbar.Update(10); err == nil {
+		t.Fatalf("Expected an error")
+	}
+	if sink.calls != 1 {
+		t.Errorf("Expected permanent errors not to be retried, got %d attempts", sink.calls)
+	}
+}
+
+func TestRetryLogged(t *testing.T) {
+	unavailable := &progress.StatusError{Code: http.StatusServiceUnavailable, Status: "503 Service Unavailable"}
+	sink := &flakySink{errs: []error{unavailable}}
+	logs := &strings.Builder{}
+	logger := slog.New(slog.NewTextHandler(logs, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	pbar := newProgress(t, sink, progress.WithTask("deploy"), progress.WithLogger(logger), progress.WithRetry(3, time.Millisecond, 5*time.Millisecond))
+
+	if err := pbar.Update(10); err != nil {
+		t.Fatalf("Error updating progress bar: %s", err)
+	}
+	if !strings.Contains(logs.String(), `msg="Retrying message" task=deploy attempt=1`) {
+		t.Errorf("Expected the retry to be logged, got %q", logs.String())
+	}
+}
+
+func TestRepostWhenEditFails(t *testing.T) {
+	pbar, sink := progresstest.New(t)
+
+	if err := pbar.Update(10); err != nil {
+		t.Fatalf("Error updating progress bar: %s", err)
+	}
+	sink.UpdateErr = progress.ErrCantEdit
+	if err := pbar.Update(50); err != nil {
+		t.Fatalf("Expected the message to be reposted, got %s", err)
+	}
+	sink.UpdateErr = nil
+	if err := pbar.Update(100); err != nil {
+		t.Fatalf("Error updating progress bar: %s", err)
+	}
+
+	if len(sink.Posts()) != 2 {
+		t.Fatalf("Expected 2 posts, got %d", len(sink.Posts()))
+	}
+	if msg := sink.Message("2"); msg == nil || !msg.Complete {
+		t.Errorf("Expected the new message to be updated, got %+v", msg)
+	}
+	if pbar.MessageTS() != "2" {
+		t.Errorf("Expected the progress bar to use the new message, got %q", pbar.MessageTS())
+	}
+}
+
+// uneditableSink posts messages that can never be edited, not even right
+// after they were posted.
+type uneditableSink struct {
+	posts int
+}
+
+func (s *uneditableSink) Post(ctx context.Context, msg *progress.Message) (string, error) {
+	s.posts++
+	if s.posts > 1 {
+		return "1", progress.ErrCantEdit
+	}
+	return "1", nil
+}
+
+func (s *uneditableSink) Update(ctx context.Context, id string, msg *progress.Message) error {
+	return progress.ErrCantEdit
+}
+
+func TestRepostOnlyOnce(t *testing.T) {
+	sink := &uneditableSink{}
+	pbar := newProgress(t, sink)
+
+	if err := pbar.Update(10); err != nil {
+		t.Fatalf("Error updating progress bar: %s", err)
+	}
+	if err := pbar.Update(50); !errors.Is(err, progress.ErrCantEdit) {
+		t.Fatalf("Expected ErrCantEdit once the repost failed, got %v", err)
+	}
+	if sink.posts != 2 {
+		t.Errorf("Expected the message to be reposted once, got %d posts", sink.posts)
+	}
+}
@@ -0,0 +1,65 @@
+package progress
+
+import (
+	"context"
+	"strings"
+)
+
+// Segment is a part of a segmented bar, e.g. the items that are done or
+// failed, drawn with its own fill.
+type Segment struct {
+	Fill  string
+	Count int64
+}
+
+// SetSegments draws the bar from segments, in order, each proportional to its
+// share of the total. The rest of the bar is drawn with Options.Empty. The
+// position becomes the sum of the counts. Invalid segments are rejected
+// without changing the bar.
+func (p *Progress) SetSegments(segments ...Segment) error {
+	return p.SetSegmentsContext(context.Background(), segments...)
+}
+
+// SetSegmentsContext is like SetSegments but gives up on sending the message
+// when ctx is cancelled or times out.
+func (p *Progress) SetSegmentsContext(ctx context.Context, segments ...Segment) error {
+	var pos int64
+	for _, seg := range segments {
+		if seg.Count < 0 {
+			return ErrNegativePos
+		}
+		pos += seg.Count
+	}
+
+	p.mu.Lock()
+	if pos > p.total() {
+		p.mu.Unlock()
+		return ErrMaxPosExceeded
+	}
+	p.segments = append([]Segment(nil), segments...)
+	p.resend = true
+	p.mu.Unlock()
+
+	return p.Update64Context(ctx, pos)
+}
+
+// drawSegments draws a bar with the segments proportional to total followed
+// by Options.Empty. Segment boundaries are rounded down so a complete bar
+// fills every cell.
+func (p *Progress) drawSegments(segments []Segment, total int64) string {
+	width := int64(p.Opts.Width)
+	bar := &strings.Builder{}
+	bar.WriteString(p.Opts.BarLeft)
+	var sum, cells int64
+
+	for _, seg := range segments {
+		sum += seg.Count
+		end := min(sum*width/total, width)
+		bar.WriteString(strings.Repeat(seg.Fill, int(end-cells)))
+		cells = end
+	}
+	bar.WriteString(strings.Repeat(p.Opts.Empty, int(width-cells)))
+	bar.WriteString(p.Opts.BarRight)
+
+	return bar.String()
+}
@@ -0,0 +1,41 @@
+package progress_test
+
+import (
+	"testing"
+
+	"github.com/sfreiberg/progress"
+)
+
+func TestSetSegments(t *testing.T) {
+	sink := &memSink{}
+	pbar := newProgress(t, sink, progress.WithTotal(9), progress.WithEmpty("."))
+
+	if err := pbar.SetSegments(progress.Segment{Fill: "D", Count: 3}, progress.Segment{Fill: "F", Count: 2}); err != nil {
+		t.Fatalf("Error setting segments: %s", err)
+	}
+	if msg := sink.posts[0]; msg.Bar != "DDDFF....." || msg.Pos != 5 {
+		t.Errorf("Expected a segmented bar at 5, got %q at %d", msg.Bar, msg.Pos)
+	}
+
+	// Rounding doesn't leave empty cells once every item is counted
+	if err := pbar.SetSegments(progress.Segment{Fill: "D", Count: 3}, progress.Segment{Fill: "F", Count: 3}, progress.Segment{Fill: "S", Count: 3}); err != nil {
+		t.Fatalf("Error setting segments: %s", err)
+	}
+	if msg := sink.updates[0]; msg.Bar != "DDDFFFSSSS" || !msg.Complete {
+		t.Errorf("Expected a full bar, got %q", msg.Bar)
+	}
+}
+
+func TestSetSegmentsInvalid(t *testing.T) {
+	pbar := newProgress(t, &memSink{}, progress.WithTotal(9), progress.WithEmpty("."))
+
+	if err := pbar.SetSegments(progress.Segment{Fill: "D", Count: 3}); err != nil {
+		t.Fatalf("Error setting segments: %s", err)
+	}
+	if err := pbar.SetSegments(progress.Segment{Fill: "D", Count: 8}, progress.Segment{Fill: "F", Count: 2}); err != progress.ErrMaxPosExceeded {
+		t.Fatalf("Expected %v, got %v", progress.ErrMaxPosExceeded, err)
+	}
+	if bar := pbar.Snapshot().Bar; bar != "DDD......." {
+		t.Errorf("Expected the previous segments to be kept, got %q", bar)
+	}
+}
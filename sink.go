@@ -0,0 +1,44 @@
+package progress
+
+import (
+	"context"
+	"time"
+)
+
+// Sink delivers rendered progress messages. Post is called the first time a
+// progress bar is sent and returns an id that identifies the message. Every
+// following change is sent with Update using that id, which returns
+// ErrSkipped if the sink chose not to send it. Implementations should give up
+// and return when ctx is cancelled.
+type Sink interface {
+	Post(ctx context.Context, msg *Message) (id string, err error)
+	Update(ctx context.Context, id string, msg *Message) error
+}
+
+// Message is a rendered progress update that is handed to a Sink.
+type Message struct {
+	Text      string        // The rendered Options.Msg template
+	Task      string        // Name of the task we are showing progress for
+	Phase     string        // The part of the task that's running, e.g. the child progress bar that was updated last
+	Status    string        // What the task is doing right now, set by Progress.SetStatus
+	Log       []string      // The last lines passed to Progress.Log
+	Bar       string        // The rendered progress bar
+	Pos       int64         // Position passed to Progress.Update
+	Total     int64         // Total possible units
+	Pct       int           // Percent complete
+	Percent   float64       // Percent complete with Options.Precision decimals
+	Complete  bool          // Whether or not the task has reached 100%
+	Paused    bool          // Whether or not Progress.Pause was called without Resume
+	Failed    bool          // Whether or not Progress.Fail was called
+	Err       error         // The error passed to Progress.Fail
+	Start     time.Time     // When the task began running. Set again by Progress.Reset.
+	Elapsed   time.Duration // Time since the task began running
+	Remaining time.Duration // Estimated time remaining
+	ETA       time.Time     // Estimated completion time. Zero if there's no estimate.
+	Rate      float64       // Units per second over Options.RateWindow
+	AvgRate   float64       // Units per second since the task began running
+	Stalled   time.Duration // How long no progress has been made, once it's longer than Options.StallAfter
+	Overdue   time.Duration // How long ago the deadline passed
+	Counts    Counts        // Items counted with Progress.IncSuccess, IncFailed and IncSkipped
+	Summary   bool          // Text is a summary that replaces the progress bar. Sinks should show only Text.
+}
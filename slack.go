@@ -0,0 +1,269 @@
+package progress
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/slack-go/slack"
+)
+
+// slackSink posts progress messages to a slack channel and edits them in place.
+type slackSink struct {
+	client  *slack.Client
+	token   string   // Token the client was created with, used to cache channel IDs
+	channel string   // Channel ID or #name to post to. Replaced with the channel ID after the first post.
+	user    string   // User ID or email to send a direct message to instead of posting to channel. Cleared once the conversation is open.
+	opts    *Options // Slack specific options such as AsUser and Blocks
+
+	tokens   TokenProvider // Provides the token before every request if set
+	reaction string        // The reaction added to the message by react
+}
+
+// NewSlackSink creates a Sink that posts to a slack channel using a bot token.
+// channel is a channel ID or a name like #deploys.
+// The slack specific fields of opts (AsUser, ThreadTS, Blocks, Buttons,
+// SlackMsgOptions, EphemeralUser, ResponseURL and HTTPClient) control how
+// messages are posted. If opts is nil then DefaultOptions are used.
+func NewSlackSink(token, channel string, opts *Options) Sink {
+	if opts == nil {
+		opts = DefaultOptions("Unknown Task")
+	}
+
+	sink := &slackSink{
+		client:  newSlackClient(token, opts),
+		token:   token,
+		channel: channel,
+		opts:    opts,
+	}
+	if opts.EphemeralUser != "" {
+		return &ephemeralSink{slack: sink, milestone: milestone{step: DefaultWebhookStep}}
+	}
+	return sink
+}
+
+// newSlackClient creates a slack client for token that sends its requests with
+// opts.HTTPClient if it's set.
+func newSlackClient(token string, opts *Options) *slack.Client {
+	if opts.HTTPClient == nil {
+		return slack.New(token)
+	}
+	return slack.New(token, slack.OptionHTTPClient(opts.HTTPClient))
+}
+
+func (s *slackSink) Post(ctx context.Context, msg *Message) (string, error) {
+	if err := s.refreshToken(ctx); err != nil {
+		return "", err
+	}
+	if err := s.openDM(ctx); err != nil {
+		return "", err
+	}
+	if err := s.resolveChannel(ctx); err != nil {
+		return "", err
+	}
+
+	msgOpts := []slack.MsgOption{
+		s.msgOptions(msg),
+		slack.MsgOptionAsUser(s.opts.AsUser),
+	}
+	if s.opts.ThreadTS != "" {
+		msgOpts = append(msgOpts, slack.MsgOptionTS(s.opts.ThreadTS))
+		if s.opts.Broadcast {
+			msgOpts = append(msgOpts, slack.MsgOptionBroadcast())
+		}
+	}
+
+	channel, ts, _, err := s.client.SendMessageContext(ctx, s.channel, msgOpts...)
+	if err != nil {
+		return "", slackError(err)
+	}
+
+	s.channel = channel
+	s.reaction = "" // A new message has no reactions yet
+	s.react(ctx, ts, msg)
+	return ts, nil
+}
+
+func (s *slackSink) Update(ctx context.Context, ts string, msg *Message) error {
+	if err := s.refreshToken(ctx); err != nil {
+		return err
+	}
+
+	_, _, _, err := s.client.UpdateMessageContext(ctx, s.channel, ts, s.msgOptions(msg))
+	if err != nil {
+		return slackError(err)
+	}
+
+	s.react(ctx, ts, msg)
+	return nil
+}
+
+// Delete deletes the message ts.
+func (s *slackSink) Delete(ctx context.Context, ts string) error {
+	if err := s.refreshToken(ctx); err != nil {
+		return err
+	}
+
+	_, _, err := s.client.DeleteMessageContext(ctx, s.channel, ts)
+	return slackError(err)
+}
+
+// Notify posts text as a reply in the thread of the progress message,
+// mentioning the given users.
+func (s *slackSink) Notify(ctx context.Context, ts, text string, mentions []string) error {
+	if err := s.refreshToken(ctx); err != nil {
+		return err
+	}
+
+	msgOpts := []slack.MsgOption{slack.MsgOptionTS(ts), slack.MsgOptionAsUser(s.opts.AsUser)}
+	if len(mentions) > 0 {
+		formatted, linkNames := formatMentions(mentions)
+		text = formatted + " " + text
+		msgOpts = append(msgOpts, slack.MsgOptionLinkNames(linkNames))
+	}
+
+	_, _, err := s.client.PostMessageContext(ctx, s.channel, append(msgOpts, slack.MsgOptionText(text, false))...)
+	return slackError(err)
+}
+
+// formatMentions formats user IDs, user group IDs and the special here,
+// channel and everyone mentions the way slack expects them. Mentions that are
+// already formatted are left alone. linkNames is true if there are @names
+// that slack has to link itself.
+func formatMentions(mentions []string) (formatted string, linkNames bool) {
+	parts := make([]string, len(mentions))
+	for i, m := range mentions {
+		switch name := strings.TrimPrefix(m, "@"); {
+		case strings.HasPrefix(m, "<"):
+			parts[i] = m
+		case name == "here" || name == "channel" || name == "everyone":
+			parts[i] = "<!" + name + ">"
+		case strings.HasPrefix(m, "@"):
+			parts[i] = m
+			linkNames = true
+		case strings.HasPrefix(m, "S"):
+			parts[i] = "<!subteam^" + m + ">"
+		default:
+			parts[i] = "<@" + m + ">"
+		}
+	}
+	return strings.Join(parts, " "), linkNames
+}
+
+// slackError converts errors returned by the slack client to the errors used
+// by this package.
+func slackError(err error) error {
+	var rl *slack.RateLimitedError
+	if errors.As(err, &rl) {
+		return &RateLimitedError{RetryAfter: rl.RetryAfter}
+	}
+
+	var se slack.StatusCodeError
+	if errors.As(err, &se) {
+		return &StatusError{Code: se.Code, Status: se.Status, Host: "slack.com"}
+	}
+
+	var re slack.SlackErrorResponse
+	if errors.As(err, &re) {
+		se := &SlackError{Code: re.Err, Err: err}
+		if re.Err == "ratelimited" {
+			// Slack doesn't say how long to wait when it answers with the
+			// error code instead of HTTP 429
+			return &RateLimitedError{RetryAfter: time.Second, Err: se}
+		}
+		return se
+	}
+
+	return err
+}
+
+// msgOptions creates the message options that are sent with every post and
+// update.
+func (s *slackSink) msgOptions(msg *Message) slack.MsgOption {
+	text := msg.Text
+	var mentions string
+	var linkNames bool
+	if (msg.Complete || msg.Failed) && len(s.opts.CompleteMentions) > 0 {
+		mentions, linkNames = formatMentions(s.opts.CompleteMentions)
+		text += "\n" + mentions
+	}
+
+	msgOpts := []slack.MsgOption{slack.MsgOptionText(text, false)}
+	if msg.Summary {
+		// Slack keeps the blocks of a message unless they're replaced
+		msgOpts = append(msgOpts, slack.MsgOptionBlocks([]slack.Block{}...))
+	} else if s.opts.Blocks {
+		blocks := s.blocks(msg)
+		if mentions != "" {
+			blocks = append(blocks, slack.NewSectionBlock(slack.NewTextBlockObject(slack.MarkdownType, mentions, false, false), nil, nil))
+		}
+		msgOpts = append(msgOpts, slack.MsgOptionBlocks(blocks...))
+	}
+	if linkNames {
+		msgOpts = append(msgOpts, slack.MsgOptionLinkNames(true))
+	}
+	if s.opts.Metadata {
+		msgOpts = append(msgOpts, slack.MsgOptionMetadata(metadata(msg)))
+	}
+	msgOpts = append(msgOpts, s.opts.SlackMsgOptions...)
+
+	return slack.MsgOptionCompose(msgOpts...)
+}
+
+// blocks renders msg as Block Kit blocks. A section shows the task and
+// progress bar, a context block the estimated time or error and an actions
+// block any Buttons.
+func (s *slackSink) blocks(msg *Message) []slack.Block {
+	task := fmt.Sprintf("*%s*", msg.Task)
+	if msg.Phase != "" {
+		task += fmt.Sprintf(" (%s)", msg.Phase)
+	}
+	bar := fmt.Sprintf("%s\n`%s` %v%%", task, msg.Bar, formatPercent(msg, s.opts.Precision))
+	if s.opts.Unit != "" {
+		current, _ := comma(msg.Pos)
+		total, _ := comma(msg.Total)
+		bar += fmt.Sprintf(" (%s / %s %s)", current, total, s.opts.Unit)
+	}
+	if msg.Status != "" {
+		bar += fmt.Sprintf("\n_%s_", msg.Status)
+	}
+	if len(msg.Log) > 0 {
+		bar += fmt.Sprintf("\n```\n%s\n```", strings.Join(msg.Log, "\n"))
+	}
+	blocks := []slack.Block{
+		slack.NewSectionBlock(slack.NewTextBlockObject(slack.MarkdownType, bar, false, false), nil, nil),
+	}
+
+	var status string
+	switch {
+	case msg.Paused && !msg.Complete:
+		status = "⏸ paused"
+	case msg.Stalled > 0:
+		status = fmt.Sprintf("⚠️ stalled for *%s*", formatDuration(s.opts.Durations, msg.Stalled))
+	case msg.Overdue > 0 && !msg.Complete:
+		status = fmt.Sprintf("🟥 overdue by *%s*", formatDuration(s.opts.Durations, msg.Overdue))
+	case msg.Failed:
+		status = fmt.Sprintf("Failed after *%s*: %v", formatDuration(s.opts.Durations, msg.Elapsed), msg.Err)
+	case !s.opts.ShowEstTime:
+	case msg.Complete:
+		status = fmt.Sprintf("Completed in *%s*", formatDuration(s.opts.Durations, msg.Elapsed))
+	default:
+		status = fmt.Sprintf("%s remaining...", formatDuration(s.opts.Durations, msg.Remaining))
+	}
+	if status != "" {
+		blocks = append(blocks, slack.NewContextBlock("", slack.NewTextBlockObject(slack.MarkdownType, status, false, false)))
+	}
+
+	// Buttons don't do anything once the task has ended
+	if len(s.opts.Buttons) > 0 && !msg.Complete && !msg.Failed {
+		elements := make([]slack.BlockElement, len(s.opts.Buttons))
+		for i, button := range s.opts.Buttons {
+			elements[i] = button
+		}
+		blocks = append(blocks, slack.NewActionBlock("progress_actions", elements...))
+	}
+
+	return blocks
+}
@@ -0,0 +1,255 @@
+package progress
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/slack-go/slack"
+)
+
+// newTestSlackSink creates a slack sink that talks to a fake slack api. Every
+// request is passed to handler after the form has been parsed.
+func newTestSlackSink(t *testing.T, opts *Options, handler func(method string, form url.Values) string) (*slackSink, func()) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			t.Errorf("Error parsing form: %s", err)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(handler(strings.TrimPrefix(r.URL.Path, "/"), r.Form)))
+	}))
+
+	sink := NewSlackSink("token", "C123", opts).(*slackSink)
+	sink.client = slack.New("token", slack.OptionAPIURL(srv.URL+"/"))
+
+	return sink, srv.Close
+}
+
+func TestSlackBlocks(t *testing.T) {
+	var blocks []string
+
+	opts := DefaultOptions("deploy")
+	opts.Blocks = true
+	opts.Buttons = []*slack.ButtonBlockElement{
+		slack.NewButtonBlockElement("abort", "abort", slack.NewTextBlockObject(slack.PlainTextType, "Abort", false, false)),
+	}
+
+	sink, done := newTestSlackSink(t, opts, func(method string, form url.Values) string {
+		blocks = append(blocks, form.Get("blocks"))
+		return `{"ok":true,"channel":"C123","ts":"1.2"}`
+	})
+	defer done()
+
+	pbar, err := NewWithSink(sink, opts)
+	if err != nil {
+		t.Fatalf("Error creating progress bar: %s", err)
+	}
+	for _, pos := range []int{50, 100} {
+		if err := pbar.Update(pos); err != nil {
+			t.Fatalf("Error updating progress bar: %s", err)
+		}
+	}
+
+	if len(blocks) != 2 {
+		t.Fatalf("Expected 2 requests, got %d", len(blocks))
+	}
+	for _, want := range []string{`"type":"section"`, "*deploy*", "remaining...", `"action_id":"abort"`} {
+		if !strings.Contains(blocks[0], want) {
+			t.Errorf("Expected blocks to contain %q, got %s", want, blocks[0])
+		}
+	}
+	if !strings.Contains(blocks[1], "Completed in") {
+		t.Errorf("Expected completed blocks, got %s", blocks[1])
+	}
+}
+
+func TestSlackThread(t *testing.T) {
+	var forms []url.Values
+
+	opts := DefaultOptions("deploy")
+	opts.ThreadTS = "1234.5678"
+	opts.Broadcast = true
+
+	sink, done := newTestSlackSink(t, opts, func(method string, form url.Values) string {
+		forms = append(forms, form)
+		return `{"ok":true,"channel":"C123","ts":"1.2"}`
+	})
+	defer done()
+
+	pbar, err := NewWithSink(sink, opts)
+	if err != nil {
+		t.Fatalf("Error creating progress bar: %s", err)
+	}
+	for _, pos := range []int{50, 100} {
+		if err := pbar.Update(pos); err != nil {
+			t.Fatalf("Error updating progress bar: %s", err)
+		}
+	}
+
+	if len(forms) != 2 {
+		t.Fatalf("Expected 2 requests, got %d", len(forms))
+	}
+	if forms[0].Get("thread_ts") != "1234.5678" || forms[0].Get("reply_broadcast") != "true" {
+		t.Errorf("Expected threaded broadcast post, got %v", forms[0])
+	}
+	if forms[1].Get("ts") != "1.2" {
+		t.Errorf("Expected update of message 1.2, got %v", forms[1])
+	}
+}
+
+func TestSlackNotify(t *testing.T) {
+	var form url.Values
+	sink, done := newTestSlackSink(t, DefaultOptions("deploy"), func(method string, f url.Values) string {
+		form = f
+		return `{"ok":true,"channel":"C123","ts":"1.3"}`
+	})
+	defer done()
+
+	if err := sink.Notify(context.Background(), "1.2", "deploy has stalled", []string{"U1", "U2"}); err != nil {
+		t.Fatalf("Error sending notification: %s", err)
+	}
+	if got := form.Get("text"); got != "<@U1> <@U2> deploy has stalled" {
+		t.Errorf("Expected the mentions before the text, got %q", got)
+	}
+	if form.Get("thread_ts") != "1.2" {
+		t.Errorf("Expected a threaded reply, got %v", form)
+	}
+}
+
+func TestSlackCompleteMentions(t *testing.T) {
+	var forms []url.Values
+
+	opts := DefaultOptions("deploy")
+	opts.CompleteMentions = []string{"U1", "S2", "@here", "<@U3>", "@oncall"}
+
+	sink, done := newTestSlackSink(t, opts, func(method string, form url.Values) string {
+		forms = append(forms, form)
+		return `{"ok":true,"channel":"C123","ts":"1.2"}`
+	})
+	defer done()
+
+	pbar, err := NewWithSink(sink, opts, WithMinInterval(0))
+	if err != nil {
+		t.Fatalf("Error creating progress bar: %s", err)
+	}
+	for _, pos := range []int{50, 100} {
+		if err := pbar.Update(pos); err != nil {
+			t.Fatalf("Error updating progress bar: %s", err)
+		}
+	}
+
+	if strings.Contains(forms[0].Get("text"), "<@U1>") {
+		t.Errorf("Expected no mentions before the task completes, got %q", forms[0].Get("text"))
+	}
+	want := "<@U1> <!subteam^S2> <!here> <@U3> @oncall"
+	if !strings.HasSuffix(forms[1].Get("text"), "\n"+want) {
+		t.Errorf("Expected the final message to end with %q, got %q", want, forms[1].Get("text"))
+	}
+	if forms[1].Get("link_names") != "true" {
+		t.Errorf("Expected link_names for @oncall, got %v", forms[1])
+	}
+}
+
+func TestSlackErrors(t *testing.T) {
+	tests := map[string][]error{
+		"message_not_found":  {ErrMessageNotFound, ErrCantEdit},
+		"edit_window_closed": {ErrCantEdit},
+		"channel_not_found":  {ErrChannelNotFound},
+		"not_in_channel":     {ErrNotInChannel},
+		"ratelimited":        {ErrRateLimited},
+	}
+
+	for code, want := range tests {
+		sink, done := newTestSlackSink(t, DefaultOptions("deploy"), func(method string, form url.Values) string {
+			return `{"ok":false,"error":"` + code + `"}`
+		})
+
+		err := sink.Update(context.Background(), "1.2", &Message{Text: "deploy"})
+		for _, target := range want {
+			if !errors.Is(err, target) {
+				t.Errorf("Expected %s to match %v, got %v", code, target, err)
+			}
+		}
+		var se *SlackError
+		if !errors.As(err, &se) || se.Code != code {
+			t.Errorf("Expected a SlackError with code %s, got %v", code, err)
+		}
+		var rl *RateLimitedError
+		if errors.As(err, &rl) != (code == "ratelimited") {
+			t.Errorf("Expected only ratelimited to be a RateLimitedError, got %v for %s", err, code)
+		}
+		if errors.Is(err, ErrNotInChannel) && code != "not_in_channel" {
+			t.Errorf("Expected %s not to match ErrNotInChannel", code)
+		}
+		done()
+	}
+
+	if err := error(&RateLimitedError{RetryAfter: time.Second}); !errors.Is(err, ErrRateLimited) {
+		t.Errorf("Expected a RateLimitedError to match ErrRateLimited")
+	}
+}
+
+// roundTripFunc lets a function be used as an http.RoundTripper.
+type roundTripFunc func(r *http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(r *http.Request) (*http.Response, error) {
+	return f(r)
+}
+
+func TestSlackHTTPClient(t *testing.T) {
+	var hosts []string
+	opts := DefaultOptions("deploy")
+	opts.HTTPClient = &http.Client{Transport: roundTripFunc(func(r *http.Request) (*http.Response, error) {
+		hosts = append(hosts, r.URL.Host)
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Header:     http.Header{"Content-Type": {"application/json"}},
+			Body:       io.NopCloser(strings.NewReader(`{"ok":true,"channel":"C123","ts":"1.2"}`)),
+		}, nil
+	})}
+
+	sink := NewSlackSink("token", "C123", opts)
+	if _, err := sink.Post(context.Background(), &Message{Text: "deploy"}); err != nil {
+		t.Fatalf("Error posting: %s", err)
+	}
+	if len(hosts) != 1 || hosts[0] != "slack.com" {
+		t.Errorf("Expected the request to slack.com to go through the client, got %v", hosts)
+	}
+}
+
+func TestGroupSendsTextWithBlocks(t *testing.T) {
+	var blocks []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			t.Errorf("Error parsing form: %s", err)
+		}
+		blocks = append(blocks, r.Form.Get("blocks"))
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"ok":true,"channel":"C123","ts":"1.2"}`))
+	}))
+	defer srv.Close()
+
+	group, err := NewGroup("token", "C123", WithBlocks(true), WithMinInterval(0))
+	if err != nil {
+		t.Fatalf("Error creating group: %s", err)
+	}
+	group.sink.(*slackSink).client = slack.New("token", slack.OptionAPIURL(srv.URL+"/"))
+
+	pbar, err := group.Add("extract")
+	if err != nil {
+		t.Fatalf("Error adding progress bar: %s", err)
+	}
+	if err := pbar.Update(50); err != nil {
+		t.Fatalf("Error updating progress bar: %s", err)
+	}
+
+	if len(blocks) != 1 || blocks[0] != "" {
+		t.Errorf("Expected the text of the bars to be sent without blocks, got %q", blocks)
+	}
+}
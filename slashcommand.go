@@ -0,0 +1,77 @@
+package progress
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/slack-go/slack"
+)
+
+// SlashCommandHandler creates an http.Handler for a slack slash command, e.g.
+// /progress, that lists the Running progress bars with their percent and
+// time remaining so they can be found after the message scrolled away.
+// Requests are verified with the app's signing secret and only the user who
+// ran the command sees the answer.
+//
+// "/progress status" lists every progress bar and "/progress status deploy"
+// only those whose task contains deploy.
+func SlashCommandHandler(signingSecret string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !verifyRequest(w, r, signingSecret) {
+			return
+		}
+
+		cmd, err := slack.SlashCommandParse(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		var text string
+		switch args := strings.Fields(cmd.Text); {
+		case len(args) == 0 || args[0] == "status":
+			text = statusText(strings.Join(args[min(len(args), 1):], " "))
+		default:
+			text = fmt.Sprintf("Usage: `%s status [task]`", cmd.Command)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(&slack.Msg{ResponseType: slack.ResponseTypeEphemeral, Text: text})
+	})
+}
+
+// statusText lists the running progress bars whose task contains filter.
+func statusText(filter string) string {
+	var lines []string
+	for _, bar := range Running() {
+		s := bar.Snapshot()
+		if !strings.Contains(strings.ToLower(s.Task), strings.ToLower(filter)) {
+			continue
+		}
+
+		line := fmt.Sprintf("• *%s*", s.Task)
+		if s.Phase != "" {
+			line += fmt.Sprintf(" (%s)", s.Phase)
+		}
+		line += fmt.Sprintf(" %d%%", s.Pct)
+		switch {
+		case s.Paused:
+			line += " ⏸ paused"
+		case s.Stalled:
+			line += " ⚠️ stalled"
+		case s.Remaining > 0:
+			line += fmt.Sprintf(", %v remaining", formatDuration(bar.Opts.Durations, s.Remaining))
+		}
+		if s.Permalink != "" {
+			line += fmt.Sprintf(" <%s|view>", s.Permalink)
+		}
+		lines = append(lines, line)
+	}
+
+	if len(lines) == 0 {
+		return "Nothing is running right now."
+	}
+	return strings.Join(lines, "\n")
+}
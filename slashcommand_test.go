@@ -0,0 +1,82 @@
+package progress_test
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sfreiberg/progress"
+	"github.com/slack-go/slack"
+)
+
+func TestSlashCommandHandler(t *testing.T) {
+	const secret = "signing-secret"
+
+	deploy := newProgress(t, &memSink{}, progress.WithTask("slash deploy"), progress.WithRegister())
+	backup := newProgress(t, &memSink{}, progress.WithTask("slash backup"), progress.WithRegister())
+	defer deploy.Close()
+	defer backup.Close()
+	if err := deploy.Update(40); err != nil {
+		t.Fatalf("Error updating progress bar: %s", err)
+	}
+
+	handler := progress.SlashCommandHandler(secret)
+	command := func(text string) slack.Msg {
+		t.Helper()
+
+		body := url.Values{"command": {"/progress"}, "text": {text}}.Encode()
+		ts := strconv.FormatInt(time.Now().Unix(), 10)
+		mac := hmac.New(sha256.New, []byte(secret))
+		mac.Write([]byte("v0:" + ts + ":" + body))
+
+		r := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+		r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		r.Header.Set("X-Slack-Request-Timestamp", ts)
+		r.Header.Set("X-Slack-Signature", "v0="+hex.EncodeToString(mac.Sum(nil)))
+
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, r)
+		if w.Code != http.StatusOK {
+			t.Fatalf("Expected %d, got %d: %s", http.StatusOK, w.Code, w.Body)
+		}
+
+		var msg slack.Msg
+		if err := json.NewDecoder(w.Body).Decode(&msg); err != nil {
+			t.Fatalf("Error decoding response: %s", err)
+		}
+		return msg
+	}
+
+	msg := command("status deploy")
+	if msg.ResponseType != slack.ResponseTypeEphemeral {
+		t.Errorf("Expected an ephemeral response, got %q", msg.ResponseType)
+	}
+	if !strings.Contains(msg.Text, "*slash deploy* 40%") || strings.Contains(msg.Text, "slash backup") {
+		t.Errorf("Expected only the deploy to be listed, got %q", msg.Text)
+	}
+
+	if msg := command("status"); !strings.Contains(msg.Text, "slash deploy") || !strings.Contains(msg.Text, "*slash backup* 0%") {
+		t.Errorf("Expected every progress bar to be listed, got %q", msg.Text)
+	}
+	if msg := command("status nothing-matches"); msg.Text != "Nothing is running right now." {
+		t.Errorf("Unexpected response without progress bars: %q", msg.Text)
+	}
+	if msg := command("help"); !strings.HasPrefix(msg.Text, "Usage: `/progress status") {
+		t.Errorf("Expected usage, got %q", msg.Text)
+	}
+
+	// An unsigned request must be rejected
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/", strings.NewReader("text=status")))
+	if w.Code != http.StatusUnauthorized {
+		t.Errorf("Expected unsigned request to be rejected, got %d", w.Code)
+	}
+}
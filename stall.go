@@ -0,0 +1,78 @@
+package progress
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// stallLoop checks for stalls a few times per Options.StallAfter until the
+// task has ended or Close or Reset is called.
+func (p *Progress) stallLoop(reset <-chan struct{}) {
+	// At least a nanosecond, NewTicker panics otherwise
+	ticker := time.NewTicker(max(p.Opts.StallAfter/4, 1))
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+		case <-p.closed:
+			return
+		case <-reset:
+			return
+		}
+
+		if !p.checkStall() {
+			return
+		}
+	}
+}
+
+// checkStall shows the task as stalled the first time no progress has been
+// made for Options.StallAfter, calls Options.OnStall and escalates with
+// Options.Escalator. Progress is the last time the position changed, even if
+// the update was throttled and hasn't been sent. It returns false once the
+// task has ended.
+func (p *Progress) checkStall() bool {
+	p.sendMu.Lock()
+	defer p.sendMu.Unlock()
+	p.mu.Lock()
+
+	pos, total := p.count.Load(), p.total()
+	if p.done || pos >= total {
+		p.mu.Unlock()
+		return false
+	}
+	if p.stalled || !p.paused.IsZero() || time.Since(p.moved) < p.Opts.StallAfter {
+		p.mu.Unlock()
+		return true
+	}
+
+	p.stalled = true
+	p.resend = true
+	// Errors are logged and passed to Options.OnError by send
+	p.update(context.Background(), pos, total)
+
+	id := p.id
+	msg := &Message{
+		Task:    p.Opts.Task,
+		Phase:   p.phase,
+		Status:  p.status,
+		Pos:     pos,
+		Total:   total,
+		Pct:     percent(pos, total),
+		Start:   p.Start,
+		Elapsed: p.elapsed(time.Now()).Round(time.Millisecond),
+		Stalled: time.Since(p.moved).Round(time.Millisecond),
+	}
+	p.mu.Unlock()
+
+	if p.Opts.OnStall != nil {
+		p.Opts.OnStall(msg)
+	}
+	p.escalate(context.Background(), msg)
+	if p.Opts.StallNotify {
+		p.notify(context.Background(), id, fmt.Sprintf("%s has stalled for %s", msg.Task, msg.Stalled))
+	}
+	return true
+}
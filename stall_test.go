@@ -0,0 +1,77 @@
+package progress_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sfreiberg/progress"
+	"github.com/sfreiberg/progress/progresstest"
+)
+
+func TestStall(t *testing.T) {
+	stalls := make(chan *progress.Message, 1)
+	opts := progress.DefaultOptions("backup")
+	opts.OnStall = func(msg *progress.Message) { stalls <- msg }
+	pbar, sink := progresstest.New(t, opts, progress.WithStall(20*time.Millisecond, true), progress.WithMentions("U123"))
+	defer pbar.Close()
+
+	if err := pbar.Update(30); err != nil {
+		t.Fatalf("Error updating progress bar: %s", err)
+	}
+
+	select {
+	case msg := <-stalls:
+		if msg.Stalled < 20*time.Millisecond {
+			t.Errorf("Expected to be stalled for at least 20ms, got %s", msg.Stalled)
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("Expected the stall to be detected")
+	}
+
+	last := sink.Last()
+	if !strings.Contains(last.Text, "stalled for") {
+		t.Errorf("Expected the message to show the stall, got %q", last.Text)
+	}
+	notes := sink.Notifications()
+	if len(notes) != 1 || notes[0].Mentions[0] != "U123" || !strings.Contains(notes[0].Text, "backup has stalled") {
+		t.Errorf("Expected a stall notification, got %+v", notes)
+	}
+
+	// Progress clears the stall
+	if err := pbar.Update(40); err != nil {
+		t.Fatalf("Error updating progress bar: %s", err)
+	}
+	if last := sink.Last(); last.Stalled != 0 || strings.Contains(last.Text, "stalled for") {
+		t.Errorf("Expected the stall to be cleared, got %q", last.Text)
+	}
+}
+
+func TestStallAfterThrottledUpdate(t *testing.T) {
+	stalls := make(chan *progress.Message, 1)
+	opts := progress.DefaultOptions("backup")
+	opts.OnStall = func(msg *progress.Message) { stalls <- msg }
+	sink := &progresstest.Sink{}
+	pbar, err := progress.NewWithSink(sink, opts, progress.WithStall(20*time.Millisecond, false), progress.WithMinInterval(time.Hour))
+	if err != nil {
+		t.Fatalf("Error creating progress bar: %s", err)
+	}
+	defer pbar.Close()
+
+	if err := pbar.Update(10); err != nil {
+		t.Fatalf("Error updating progress bar: %s", err)
+	}
+	// Throttled, but it's still progress
+	if err := pbar.Update(20); err != nil {
+		t.Fatalf("Error updating progress bar: %s", err)
+	}
+
+	select {
+	case msg := <-stalls:
+		if msg.Pos != 20 {
+			t.Errorf("Expected the stall to be at the last position, got %d", msg.Pos)
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("Expected the stall to be detected")
+	}
+}
@@ -0,0 +1,81 @@
+package progress
+
+import (
+	"encoding/json"
+	"io"
+	"time"
+)
+
+// state is the part of a progress bar that's saved by Save.
+type state struct {
+	Channel   string    `json:"channel,omitempty"` // The slack channel ID
+	MessageTS string    `json:"message_ts,omitempty"`
+	Start     time.Time `json:"start"`
+	Pos       int64     `json:"pos"`
+	Total     int64     `json:"total"`
+}
+
+// Save writes the state of the progress bar (the message it's editing, when
+// the task started, the position and the total) to w as JSON so the progress
+// bar can be resumed after a restart with Load.
+func (p *Progress) Save(w io.Writer) error {
+	p.sendMu.Lock()
+	defer p.sendMu.Unlock()
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	s := state{
+		MessageTS: p.id,
+		Start:     p.Start,
+		Pos:       p.count.Load(),
+		Total:     p.total(),
+	}
+	if slack, ok := p.sink.(*slackSink); ok {
+		s.Channel = slack.channel
+	}
+
+	return json.NewEncoder(w).Encode(s)
+}
+
+// Load restores the state written by Save so the progress bar keeps editing
+// the same message, counting from the saved position. Call it before the
+// first update.
+func (p *Progress) Load(r io.Reader) error {
+	var s state
+	if err := json.NewDecoder(r).Decode(&s); err != nil {
+		return err
+	}
+	if s.Total <= 0 {
+		return ErrInvalidTotal
+	}
+	if s.Pos < 0 {
+		return ErrNegativePos
+	}
+	if s.Pos > s.Total {
+		return ErrMaxPosExceeded
+	}
+
+	p.sendMu.Lock()
+	defer p.sendMu.Unlock()
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if slack, ok := p.sink.(*slackSink); ok && s.Channel != "" {
+		slack.channel = s.Channel
+	}
+	if s.Total != p.total() {
+		p.Opts.Total64 = s.Total
+	}
+
+	p.id = s.MessageTS
+	p.Start = s.Start
+	p.count.Store(s.Pos)
+	p.pending = s.Pos
+	p.lastPos = s.Pos
+	p.lastPct = percent(s.Pos, s.Total)
+	p.lastStep = p.step(s.Pos, s.Total)
+	p.moved = time.Now()
+	p.movedPos = s.Pos
+	p.rearmDeadline()
+	return nil
+}
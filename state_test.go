@@ -0,0 +1,48 @@
+package progress_test
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/sfreiberg/progress"
+)
+
+func TestSaveLoad(t *testing.T) {
+	sink := &memSink{}
+	pbar := newProgress(t, sink, progress.WithTotal64(200))
+	if err := pbar.Update(80); err != nil {
+		t.Fatalf("Error updating progress bar: %s", err)
+	}
+
+	saved := &bytes.Buffer{}
+	if err := pbar.Save(saved); err != nil {
+		t.Fatalf("Error saving progress bar: %s", err)
+	}
+
+	resumed := newProgress(t, sink)
+	if err := resumed.Load(saved); err != nil {
+		t.Fatalf("Error loading progress bar: %s", err)
+	}
+	if resumed.MessageTS() != "1" || !resumed.Start.Equal(pbar.Start) {
+		t.Errorf("Expected the message ts and start time to be restored, got %q and %s", resumed.MessageTS(), resumed.Start)
+	}
+
+	if err := resumed.Add(20); err != nil {
+		t.Fatalf("Error updating progress bar: %s", err)
+	}
+	if len(sink.posts) != 1 || len(sink.updates) != 1 {
+		t.Fatalf("Expected the saved message to be edited, got %d posts and %d updates", len(sink.posts), len(sink.updates))
+	}
+	if msg := sink.updates[0]; msg.Pos != 100 || msg.Pct != 50 {
+		t.Errorf("Expected position 100 at 50%%, got %d at %d%%", msg.Pos, msg.Pct)
+	}
+}
+
+func TestLoadInvalid(t *testing.T) {
+	pbar := newProgress(t, &memSink{})
+	saved := bytes.NewBufferString(`{"start":"` + time.Now().Format(time.RFC3339) + `","pos":20,"total":10}`)
+	if err := pbar.Load(saved); err != progress.ErrMaxPosExceeded {
+		t.Errorf("Expected ErrMaxPosExceeded, got %v", err)
+	}
+}
@@ -0,0 +1,48 @@
+package progress
+
+import "context"
+
+// SetStatus shows what the task is doing right now, e.g. "processing
+// users.csv (4/27)". It's available in templates as .Status and re-renders
+// the message, subject to Options.MinInterval. An empty status removes it.
+func (p *Progress) SetStatus(status string) error {
+	return p.SetStatusContext(context.Background(), status)
+}
+
+// SetStatusContext is like SetStatus but gives up on sending the message when
+// ctx is cancelled or times out.
+func (p *Progress) SetStatusContext(ctx context.Context, status string) error {
+	return p.rerender(ctx, func() bool {
+		if status == p.status {
+			return false
+		}
+		p.status = status
+		return true
+	})
+}
+
+// rerender calls change with p.mu held and re-renders the message at the
+// current position if it returns true.
+func (p *Progress) rerender(ctx context.Context, change func() bool) error {
+	// In async mode the background sender does the sending
+	if p.async == nil {
+		p.sendMu.Lock()
+		defer p.sendMu.Unlock()
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if !change() {
+		return nil
+	}
+	p.resend = true
+
+	if p.async != nil {
+		p.pending = p.count.Load()
+		p.async.wake()
+		return nil
+	}
+
+	return p.update(ctx, p.count.Load(), p.total())
+}
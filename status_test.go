@@ -0,0 +1,36 @@
+package progress_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/sfreiberg/progress"
+)
+
+func TestSetStatus(t *testing.T) {
+	sink := &memSink{}
+	pbar := newProgress(t, sink, progress.WithTask("import"))
+
+	if err := pbar.Update(10); err != nil {
+		t.Fatalf("Error updating progress bar: %s", err)
+	}
+	if err := pbar.SetStatus("processing users.csv (4/27)"); err != nil {
+		t.Fatalf("Error setting status: %s", err)
+	}
+
+	if len(sink.updates) != 1 {
+		t.Fatalf("Expected the status to re-render the message, got %d updates", len(sink.updates))
+	}
+	msg := sink.updates[0]
+	if msg.Status != "processing users.csv (4/27)" || !strings.Contains(msg.Text, "_processing users.csv (4/27)_") {
+		t.Errorf("Expected the status in the message, got %q", msg.Text)
+	}
+
+	// The same status isn't sent twice
+	if err := pbar.SetStatus("processing users.csv (4/27)"); err != nil {
+		t.Fatalf("Error setting status: %s", err)
+	}
+	if len(sink.updates) != 1 {
+		t.Errorf("Expected no update for an unchanged status, got %d updates", len(sink.updates))
+	}
+}
@@ -0,0 +1,140 @@
+package progress
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+)
+
+// streamBuffer is how many events are buffered for a client before new ones
+// are dropped.
+const streamBuffer = 16
+
+// streamEvent is a progress message sent to the clients of an EventStream.
+type streamEvent struct {
+	ID string `json:"id"` // Identifies the progress bar when several share the stream
+	jsonLine
+}
+
+// EventStream is a Sink that streams every progress message to browsers as
+// server-sent events, e.g. for a live progress widget in an admin page.
+// Combine it with a slack sink using NewMultiSink so the browser sees what's
+// sent to slack. Several progress bars can share one EventStream and each
+// event has the id of its progress bar.
+//
+// Every event is a JSON object with the fields written by NewJSONLinesSink
+// and the id. Clients that connect late are sent the latest event of every
+// progress bar that's still running first. Events for clients that can't
+// keep up are dropped.
+type EventStream struct {
+	mu      sync.Mutex
+	nextID  int
+	latest  map[string][]byte // The latest event of every running progress bar
+	order   []string          // The ids in latest in the order they were posted
+	clients map[chan []byte]struct{}
+}
+
+// NewEventStream creates an EventStream without clients. Serve it with an
+// http.Server to let clients connect.
+func NewEventStream() *EventStream {
+	return &EventStream{
+		latest:  map[string][]byte{},
+		clients: map[chan []byte]struct{}{},
+	}
+}
+
+func (s *EventStream) Post(ctx context.Context, msg *Message) (string, error) {
+	s.mu.Lock()
+	s.nextID++
+	id := strconv.Itoa(s.nextID)
+	s.mu.Unlock()
+
+	return id, s.Update(ctx, id, msg)
+}
+
+func (s *EventStream) Update(ctx context.Context, id string, msg *Message) error {
+	data, err := json.Marshal(streamEvent{ID: id, jsonLine: newJSONLine(msg)})
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.latest[id]; !ok {
+		s.order = append(s.order, id)
+	}
+	s.latest[id] = data
+	if msg.Complete || msg.Failed {
+		s.forget(id)
+	}
+
+	for client := range s.clients {
+		select {
+		case client <- data:
+		default: // The client is too slow, it will catch up with the next event
+		}
+	}
+	return nil
+}
+
+// forget stops sending the latest event of the progress bar id to clients
+// that connect later. s.mu must be held.
+func (s *EventStream) forget(id string) {
+	delete(s.latest, id)
+	for i, other := range s.order {
+		if other == id {
+			s.order = append(s.order[:i], s.order[i+1:]...)
+			return
+		}
+	}
+}
+
+// ServeHTTP streams events to the client until it disconnects.
+func (s *EventStream) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "Streaming not supported", http.StatusInternalServerError)
+		return
+	}
+
+	client := make(chan []byte, streamBuffer)
+	s.mu.Lock()
+	replay := make([][]byte, len(s.order))
+	for i, id := range s.order {
+		replay[i] = s.latest[id]
+	}
+	s.clients[client] = struct{}{}
+	s.mu.Unlock()
+
+	defer func() {
+		s.mu.Lock()
+		delete(s.clients, client)
+		s.mu.Unlock()
+	}()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+	for _, data := range replay {
+		if _, err := fmt.Fprintf(w, "event: progress\ndata: %s\n\n", data); err != nil {
+			return
+		}
+	}
+	flusher.Flush()
+
+	for {
+		select {
+		case data := <-client:
+			if _, err := fmt.Fprintf(w, "event: progress\ndata: %s\n\n", data); err != nil {
+				return
+			}
+			flusher.Flush()
+		case <-r.Context().Done():
+			return
+		}
+	}
+}
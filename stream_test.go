@@ -0,0 +1,69 @@
+package progress_test
+
+import (
+	"bufio"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/sfreiberg/progress"
+)
+
+func TestEventStream(t *testing.T) {
+	stream := progress.NewEventStream()
+	srv := httptest.NewServer(stream)
+	defer srv.Close()
+
+	pbar := newProgress(t, stream, progress.WithTask("import"))
+	if err := pbar.Update(30); err != nil {
+		t.Fatalf("Error updating progress bar: %s", err)
+	}
+
+	resp, err := http.Get(srv.URL)
+	if err != nil {
+		t.Fatalf("Error connecting to stream: %s", err)
+	}
+	defer resp.Body.Close()
+	if ct := resp.Header.Get("Content-Type"); ct != "text/event-stream" {
+		t.Fatalf("Expected an event stream, got %q", ct)
+	}
+
+	events := bufio.NewScanner(resp.Body)
+	next := func() map[string]interface{} {
+		t.Helper()
+		for events.Scan() {
+			data, ok := strings.CutPrefix(events.Text(), "data: ")
+			if !ok {
+				continue
+			}
+			var event map[string]interface{}
+			if err := json.Unmarshal([]byte(data), &event); err != nil {
+				t.Fatalf("Error decoding %q: %s", data, err)
+			}
+			return event
+		}
+		t.Fatalf("Stream ended: %v", events.Err())
+		return nil
+	}
+
+	// The client catches up with the progress made before it connected
+	if event := next(); event["id"] != "1" || event["task"] != "import" || event["pct"] != 30.0 {
+		t.Errorf("Unexpected first event: %v", event)
+	}
+
+	if err := pbar.Update(60); err != nil {
+		t.Fatalf("Error updating progress bar: %s", err)
+	}
+	if event := next(); event["pct"] != 60.0 {
+		t.Errorf("Unexpected update event: %v", event)
+	}
+
+	if err := pbar.Finish(); err != nil {
+		t.Fatalf("Error finishing progress bar: %s", err)
+	}
+	if event := next(); event["complete"] != true {
+		t.Errorf("Unexpected final event: %v", event)
+	}
+}
@@ -0,0 +1,155 @@
+package progress
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+)
+
+const (
+	adaptiveCardType = "application/vnd.microsoft.card.adaptive"
+	graphURL         = "https://graph.microsoft.com/v1.0"
+)
+
+// teamsWebhookSink posts progress messages as adaptive cards to a Microsoft
+// Teams incoming webhook. Like slack webhooks they can't be edited so a new
+// card is posted every time the progress bar crosses a milestone.
+type teamsWebhookSink struct {
+	url       string
+	milestone milestone
+}
+
+// NewTeamsSink creates a Sink that posts adaptive cards to a Microsoft Teams
+// incoming webhook. Since incoming webhooks can't edit messages a new card is
+// posted every step percent and when the task completes.
+func NewTeamsSink(webhookURL string, step int) Sink {
+	if step <= 0 {
+		step = DefaultWebhookStep
+	}
+
+	return &teamsWebhookSink{
+		url:       webhookURL,
+		milestone: milestone{step: step},
+	}
+}
+
+// NewTeams creates a new progress bar that posts to a Microsoft Teams incoming
+// webhook. A new card is posted every DefaultWebhookStep percent. Use
+// NewTeamsGraphSink to update a single card instead. Progress is created with
+// DefaultOptions customized by opts.
+func NewTeams(webhookURL string, opts ...Option) (*Progress, error) {
+	return NewWithSink(NewTeamsSink(webhookURL, DefaultWebhookStep), opts...)
+}
+
+func (s *teamsWebhookSink) Post(ctx context.Context, msg *Message) (string, error) {
+	if err := s.send(ctx, msg); err != nil {
+		return "", err
+	}
+
+	// There's nothing to edit so any non empty id will do
+	return s.url, nil
+}
+
+func (s *teamsWebhookSink) Update(ctx context.Context, id string, msg *Message) error {
+	if !s.milestone.crossed(msg) {
+		return ErrSkipped
+	}
+
+	return s.send(ctx, msg)
+}
+
+func (s *teamsWebhookSink) send(ctx context.Context, msg *Message) error {
+	payload := map[string]interface{}{
+		"type": "message",
+		"attachments": []map[string]interface{}{{
+			"contentType": adaptiveCardType,
+			"content":     adaptiveCard(msg),
+		}},
+	}
+	if err := postJSON(ctx, s.url, payload, nil); err != nil {
+		return err
+	}
+
+	s.milestone.mark(msg)
+	return nil
+}
+
+// teamsGraphSink posts progress messages as adaptive cards to a Microsoft
+// Teams channel using the Graph API and edits the card in place.
+type teamsGraphSink struct {
+	token string
+	url   string // Messages url of the channel
+}
+
+// NewTeamsGraphSink creates a Sink that posts an adaptive card to a Microsoft
+// Teams channel with the Graph API and updates the same card as progress
+// changes. token must be an OAuth access token that is allowed to send and
+// edit channel messages.
+func NewTeamsGraphSink(token, teamID, channelID string) Sink {
+	return &teamsGraphSink{
+		token: token,
+		url:   graphURL + "/teams/" + teamID + "/channels/" + channelID + "/messages",
+	}
+}
+
+func (s *teamsGraphSink) Post(ctx context.Context, msg *Message) (string, error) {
+	body, err := s.body(msg)
+	if err != nil {
+		return "", err
+	}
+
+	var resp struct{ ID string }
+	if err := sendJSON(ctx, http.MethodPost, s.url, s.header(), body, &resp); err != nil {
+		return "", err
+	}
+
+	return resp.ID, nil
+}
+
+func (s *teamsGraphSink) Update(ctx context.Context, id string, msg *Message) error {
+	body, err := s.body(msg)
+	if err != nil {
+		return err
+	}
+
+	return sendJSON(ctx, http.MethodPatch, s.url+"/"+id, s.header(), body, nil)
+}
+
+// body creates a chat message that contains msg as an adaptive card.
+func (s *teamsGraphSink) body(msg *Message) (interface{}, error) {
+	// The Graph API expects the card itself as a json encoded string
+	card, err := json.Marshal(adaptiveCard(msg))
+	if err != nil {
+		return nil, err
+	}
+
+	return map[string]interface{}{
+		"body": map[string]string{
+			"contentType": "html",
+			"content":     `<attachment id="progress"></attachment>`,
+		},
+		"attachments": []map[string]string{{
+			"id":          "progress",
+			"contentType": adaptiveCardType,
+			"content":     string(card),
+		}},
+	}, nil
+}
+
+func (s *teamsGraphSink) header() http.Header {
+	return http.Header{"Authorization": {"Bearer " + s.token}}
+}
+
+// adaptiveCard renders msg as a Microsoft Teams adaptive card.
+func adaptiveCard(msg *Message) map[string]interface{} {
+	return map[string]interface{}{
+		"$schema": "http://adaptivecards.io/schemas/adaptive-card.json",
+		"type":    "AdaptiveCard",
+		"version": "1.2",
+		"body": []map[string]interface{}{{
+			"type": "TextBlock",
+			"text": msg.Text,
+			"wrap": true,
+		}},
+	}
+}
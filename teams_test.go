@@ -0,0 +1,49 @@
+package progress_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/sfreiberg/progress"
+)
+
+func TestTeams(t *testing.T) {
+	var cards []map[string]interface{}
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var payload struct {
+			Attachments []struct {
+				ContentType string
+				Content     map[string]interface{}
+			}
+		}
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			t.Errorf("Error decoding teams payload: %s", err)
+		}
+		if len(payload.Attachments) != 1 {
+			t.Errorf("Expected 1 attachment, got %d", len(payload.Attachments))
+			return
+		}
+		cards = append(cards, payload.Attachments[0].Content)
+	}))
+	defer srv.Close()
+
+	pbar, err := progress.NewTeams(srv.URL, progress.WithMinInterval(0))
+	if err != nil {
+		t.Fatalf("Error creating progress bar: %s", err)
+	}
+	for i := 0; i <= pbar.Opts.TotalUnits; i++ {
+		if err := pbar.Update(i); err != nil {
+			t.Fatalf("Error updating progress bar: %s", err)
+		}
+	}
+
+	if len(cards) != 5 {
+		t.Fatalf("Expected 5 cards, got %d", len(cards))
+	}
+	if cards[0]["type"] != "AdaptiveCard" {
+		t.Errorf("Expected an adaptive card, got %v", cards[0]["type"])
+	}
+}
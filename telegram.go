@@ -0,0 +1,77 @@
+package progress
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+)
+
+const telegramURL = "https://api.telegram.org"
+
+// telegramSink posts progress messages with the Telegram Bot API and edits
+// them in place with editMessageText.
+type telegramSink struct {
+	url    string // Bot API url including the token
+	chatID string
+}
+
+// NewTelegramSink creates a Sink that posts to a Telegram chat using a bot
+// token. chatID is either the numeric id of the chat or @channelusername.
+func NewTelegramSink(token, chatID string) Sink {
+	return &telegramSink{
+		url:    telegramURL + "/bot" + token,
+		chatID: chatID,
+	}
+}
+
+// NewTelegram creates a new progress bar that posts to a Telegram chat.
+// Progress is created with DefaultOptions customized by opts.
+func NewTelegram(token, chatID string, opts ...Option) (*Progress, error) {
+	return NewWithSink(NewTelegramSink(token, chatID), opts...)
+}
+
+func (s *telegramSink) Post(ctx context.Context, msg *Message) (string, error) {
+	payload := map[string]interface{}{
+		"chat_id": s.chatID,
+		"text":    msg.Text,
+	}
+
+	var resp struct {
+		Result struct {
+			MessageID int64 `json:"message_id"`
+		}
+	}
+	if err := s.call(ctx, "sendMessage", payload, &resp); err != nil {
+		return "", err
+	}
+
+	return strconv.FormatInt(resp.Result.MessageID, 10), nil
+}
+
+func (s *telegramSink) Update(ctx context.Context, id string, msg *Message) error {
+	messageID, err := strconv.ParseInt(id, 10, 64)
+	if err != nil {
+		return err
+	}
+
+	payload := map[string]interface{}{
+		"chat_id":    s.chatID,
+		"message_id": messageID,
+		"text":       msg.Text,
+	}
+
+	return s.call(ctx, "editMessageText", payload, nil)
+}
+
+// call calls the Bot API method with payload. The url contains the bot token,
+// so it's dropped from *url.Error to keep the token out of logs.
+func (s *telegramSink) call(ctx context.Context, method string, payload, out interface{}) error {
+	err := postJSON(ctx, s.url+"/"+method, payload, out)
+	var uerr *url.Error
+	if errors.As(err, &uerr) {
+		return fmt.Errorf("telegram %s: %w", method, uerr.Err)
+	}
+	return err
+}
@@ -0,0 +1,74 @@
+package progress
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestTelegram(t *testing.T) {
+	var edits int
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var payload struct {
+			ChatID    string `json:"chat_id"`
+			MessageID int64  `json:"message_id"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			t.Errorf("Error decoding telegram payload: %s", err)
+		}
+		if payload.ChatID != "@builds" {
+			t.Errorf("Expected chat @builds, got %q", payload.ChatID)
+		}
+
+		switch r.URL.Path {
+		case "/bottoken/sendMessage":
+			w.Write([]byte(`{"ok":true,"result":{"message_id":42}}`))
+		case "/bottoken/editMessageText":
+			if payload.MessageID != 42 {
+				t.Errorf("Expected message 42 to be edited, got %d", payload.MessageID)
+			}
+			edits++
+			w.Write([]byte(`{"ok":true,"result":{"message_id":42}}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	sink := NewTelegramSink("token", "@builds").(*telegramSink)
+	sink.url = srv.URL + "/bottoken"
+
+	pbar, err := NewWithSink(sink, WithMinInterval(0))
+	if err != nil {
+		t.Fatalf("Error creating progress bar: %s", err)
+	}
+	for i := 0; i <= 10; i++ {
+		if err := pbar.Update(i); err != nil {
+			t.Fatalf("Error updating progress bar: %s", err)
+		}
+	}
+
+	if edits != 9 {
+		t.Errorf("Expected 9 edits, got %d", edits)
+	}
+}
+
+func TestTelegramErrorHidesToken(t *testing.T) {
+	srv := httptest.NewServer(http.NotFoundHandler())
+	srv.Close() // Requests fail with a connection error
+
+	sink := NewTelegramSink("secret-token", "@builds").(*telegramSink)
+	sink.url = srv.URL + "/botsecret-token"
+
+	_, err := sink.Post(context.Background(), &Message{Text: "50%"})
+	if err == nil {
+		t.Fatal("Expected an error posting to a closed server")
+	}
+	if strings.Contains(err.Error(), "secret-token") {
+		t.Errorf("Expected the token to be left out of the error, got %q", err)
+	}
+}
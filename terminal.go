@@ -0,0 +1,52 @@
+package progress
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// terminalSink draws progress messages on a terminal, redrawing the message in
+// place with ANSI escape codes.
+type terminalSink struct {
+	w     io.Writer
+	lines int // Number of lines the last message took up
+}
+
+// NewTerminalSink creates a Sink that draws the progress bar on the terminal
+// w, usually os.Stdout or os.Stderr. Every update replaces the previous
+// message, so nothing else should be written to w in between.
+func NewTerminalSink(w io.Writer) Sink {
+	return &terminalSink{w: w}
+}
+
+func (s *terminalSink) Post(ctx context.Context, msg *Message) (string, error) {
+	if err := s.write(msg, false); err != nil {
+		return "", err
+	}
+
+	// There's only one message on the terminal so any non empty id will do
+	return "terminal", nil
+}
+
+func (s *terminalSink) Update(ctx context.Context, id string, msg *Message) error {
+	return s.write(msg, true)
+}
+
+// write writes msg, first moving the cursor up and clearing the previous
+// message if replace is true.
+func (s *terminalSink) write(msg *Message, replace bool) error {
+	var b strings.Builder
+	if replace && s.lines > 0 {
+		fmt.Fprintf(&b, "\x1b[%dA\x1b[J", s.lines)
+	}
+	text := strings.TrimRight(msg.Text, "\n")
+	b.WriteString(text + "\n")
+
+	if _, err := io.WriteString(s.w, b.String()); err != nil {
+		return err
+	}
+	s.lines = strings.Count(text, "\n") + 1
+	return nil
+}
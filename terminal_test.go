@@ -0,0 +1,25 @@
+package progress_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/sfreiberg/progress"
+)
+
+func TestTerminal(t *testing.T) {
+	var out strings.Builder
+	pbar, err := progress.New("", "", progress.WithTerminal(&out), progress.WithMinInterval(0), progress.WithTemplate("{{.Task}}\n{{.Pos}}%"), progress.WithTask("build"))
+	if err != nil {
+		t.Fatalf("Error creating progress bar: %s", err)
+	}
+	for _, pos := range []int{50, 100} {
+		if err := pbar.Update(pos); err != nil {
+			t.Fatalf("Error updating progress bar: %s", err)
+		}
+	}
+
+	if want := "build\n50%\n\x1b[2A\x1b[Jbuild\n100%\n"; out.String() != want {
+		t.Errorf("Expected %q, got %q", want, out.String())
+	}
+}
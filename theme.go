@@ -0,0 +1,79 @@
+package progress
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// Theme is a named set of characters for drawing the progress bar.
+type Theme struct {
+	Fill     string
+	Empty    string
+	FailFill string   // Used after Progress.Fail. Options.FailFill is kept if it's empty.
+	Fills    []string // Fills picked by percent complete, e.g. red, yellow and green. Used instead of Fill if set.
+	Left     string   // Drawn before the bar
+	Right    string   // Drawn after the bar
+}
+
+var (
+	themesMu sync.RWMutex
+	themes   = map[string]Theme{
+		"classic":       {Fill: "⬛", Empty: "⬜"},
+		"circles":       {Fill: "🔵", Empty: "⚪"},
+		"moons":         {Fill: "🌕", Empty: "🌑"},
+		"traffic-light": {Fills: []string{"🟥", "🟨", "🟩"}, Empty: "⬜"},
+		"ascii":         {Fill: "=", Empty: " ", FailFill: "x", Left: "[", Right: "]"},
+	}
+)
+
+// RegisterTheme makes a theme available to Options.Theme under name. It
+// replaces any theme registered with the same name, including the built in
+// classic, circles, moons, traffic-light and ascii themes.
+func RegisterTheme(name string, theme Theme) {
+	themesMu.Lock()
+	defer themesMu.Unlock()
+	themes[name] = theme
+}
+
+// Themes returns the names of the registered themes in alphabetical order.
+func Themes() []string {
+	themesMu.RLock()
+	defer themesMu.RUnlock()
+
+	names := make([]string, 0, len(themes))
+	for name := range themes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// applyTheme replaces the bar characters in opts with those of the theme
+// named by opts.Theme.
+func applyTheme(opts *Options) error {
+	themesMu.RLock()
+	theme, ok := themes[opts.Theme]
+	themesMu.RUnlock()
+	if !ok {
+		return fmt.Errorf("Unknown theme %q", opts.Theme)
+	}
+
+	opts.Fill = theme.Fill
+	opts.Empty = theme.Empty
+	opts.Fills = theme.Fills
+	opts.BarLeft = theme.Left
+	opts.BarRight = theme.Right
+	if theme.FailFill != "" {
+		opts.FailFill = theme.FailFill
+	}
+	return nil
+}
+
+// fill returns the character used to fill a bar at pct.
+func (p *Progress) fill(pct int) string {
+	if n := len(p.Opts.Fills); n > 0 {
+		return p.Opts.Fills[min(pct*n/100, n-1)]
+	}
+	return p.Opts.Fill
+}
@@ -0,0 +1,64 @@
+package progress_test
+
+import (
+	"testing"
+
+	"github.com/sfreiberg/progress"
+)
+
+func TestThemes(t *testing.T) {
+	tests := []struct {
+		theme string
+		pos   int
+		bar   string
+	}{
+		{"moons", 30, "🌕🌕🌕🌑🌑🌑🌑🌑🌑🌑"},
+		{"ascii", 50, "[=====     ]"},
+		{"traffic-light", 20, "🟥🟥⬜⬜⬜⬜⬜⬜⬜⬜"},
+		{"traffic-light", 90, "🟩🟩🟩🟩🟩🟩🟩🟩🟩⬜"},
+	}
+
+	for _, test := range tests {
+		sink := &memSink{}
+		pbar := newProgress(t, sink, progress.WithTheme(test.theme))
+		if err := pbar.Update(test.pos); err != nil {
+			t.Fatalf("Error updating progress bar: %s", err)
+		}
+		if bar := sink.posts[0].Bar; bar != test.bar {
+			t.Errorf("Expected %s at %d%% to be %q, got %q", test.theme, test.pos, test.bar, bar)
+		}
+	}
+}
+
+func TestRegisterTheme(t *testing.T) {
+	progress.RegisterTheme("hearts", progress.Theme{Fill: "❤️", Empty: "🤍", FailFill: "💔"})
+
+	sink := &memSink{}
+	pbar := newProgress(t, sink, progress.WithTheme("hearts"))
+	if pbar.Opts.Fill != "❤️" || pbar.Opts.FailFill != "💔" {
+		t.Errorf("Expected the registered theme to be used, got %+v", pbar.Opts)
+	}
+
+	if _, err := progress.NewWithSink(sink, progress.WithTheme("sparkles")); err == nil {
+		t.Errorf("Expected an error for an unknown theme")
+	}
+}
+
+func TestSubBlocks(t *testing.T) {
+	sink := &memSink{}
+	pbar := newProgress(t, sink, progress.WithSubBlocks(), progress.WithEmpty(" "))
+
+	for _, pos := range []int{5, 37, 100} {
+		if err := pbar.Update(pos); err != nil {
+			t.Fatalf("Error updating progress bar: %s", err)
+		}
+	}
+
+	want := []string{"▌         ", "███▋      ", "██████████"}
+	got := []string{sink.posts[0].Bar, sink.updates[0].Bar, sink.updates[1].Bar}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("Expected %q, got %q", want[i], got[i])
+		}
+	}
+}
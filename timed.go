@@ -0,0 +1,71 @@
+package progress
+
+import (
+	"context"
+	"math"
+	"time"
+)
+
+// NewTimed creates a progress bar that's driven by time alone, e.g. for a
+// maintenance window or a cache warm-up of predictable length. The bar fills
+// up as d passes, updating itself, and completes once d has passed. The
+// position is in milliseconds. Time spent paused doesn't count, Fail or Close
+// stop it early. See New for how the sink is chosen.
+func NewTimed(token, channel string, d time.Duration, opts ...Option) (*Progress, error) {
+	o := buildOptions(opts)
+	return NewTimedWithSink(newSink(token, channel, o), d, o)
+}
+
+// NewTimedWithSink is like NewTimed but delivers its messages to sink.
+func NewTimedWithSink(sink Sink, d time.Duration, opts ...Option) (*Progress, error) {
+	if d <= 0 {
+		return nil, ErrInvalidTotal
+	}
+
+	total := max(d.Milliseconds(), 1)
+	p, err := NewWithSink(sink, append(opts, WithTotal64(total))...)
+	if err != nil {
+		return nil, err
+	}
+	// Post the empty bar right away
+	if err := p.rerender(context.Background(), func() bool { return true }); err != nil {
+		p.Close()
+		return nil, err
+	}
+
+	// Send an update for every step shown, but not more often than allowed
+	interval := max(d/time.Duration(100*math.Pow10(p.Opts.Precision)), p.Opts.MinInterval, time.Millisecond)
+	go p.timedLoop(d, interval)
+	return p, nil
+}
+
+// timedLoop moves the position along with the time elapsed every interval
+// until d has passed, the task has ended or Close is called.
+func (p *Progress) timedLoop(d, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+		case <-p.closed:
+			return
+		}
+
+		p.mu.Lock()
+		ended := p.done || p.lastPct == 100
+		elapsed := p.elapsed(time.Now())
+		p.mu.Unlock()
+
+		// Errors are logged and passed to Options.OnError by send
+		switch {
+		case ended:
+			return
+		case elapsed >= d:
+			p.Finish()
+			return
+		default:
+			p.Update64(elapsed.Milliseconds())
+		}
+	}
+}
@@ -0,0 +1,37 @@
+package progress_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/sfreiberg/progress"
+	"github.com/sfreiberg/progress/progresstest"
+)
+
+func TestTimed(t *testing.T) {
+	sink := &progresstest.Sink{}
+	pbar, err := progress.NewTimedWithSink(sink, 200*time.Millisecond, progress.WithMinInterval(0))
+	if err != nil {
+		t.Fatalf("Error creating progress bar: %s", err)
+	}
+	defer pbar.Close()
+
+	if posts := sink.Posts(); len(posts) != 1 || posts[0].Pct != 0 {
+		t.Fatalf("Expected the progress bar to be posted right away")
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for last := sink.Last(); !last.Complete; last = sink.Last() {
+		if time.Now().After(deadline) {
+			t.Fatalf("Expected the progress bar to complete, got %d%%", last.Pct)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	if updates := len(sink.Updates()); updates < 3 {
+		t.Errorf("Expected the progress bar to fill up gradually, got %d updates", updates)
+	}
+	if _, err := progress.NewTimedWithSink(sink, 0); err != progress.ErrInvalidTotal {
+		t.Errorf("Expected %v, got %v", progress.ErrInvalidTotal, err)
+	}
+}
@@ -0,0 +1,62 @@
+package progress
+
+import "context"
+
+// TokenProvider provides the slack token, e.g. from Vault or AWS Secrets
+// Manager. Token is called before every message is sent so a rotated token
+// is picked up without recreating the progress bar. Implementations should
+// cache the token rather than fetch it every time.
+type TokenProvider interface {
+	Token(ctx context.Context) (string, error)
+}
+
+// TokenProviderFunc is a function that implements TokenProvider.
+type TokenProviderFunc func(ctx context.Context) (string, error)
+
+// Token calls f(ctx).
+func (f TokenProviderFunc) Token(ctx context.Context) (string, error) {
+	return f(ctx)
+}
+
+// NewSlackSinkWithTokenProvider is like NewSlackSink but gets the token from
+// tokens every time a message is sent.
+func NewSlackSinkWithTokenProvider(tokens TokenProvider, channel string, opts *Options) Sink {
+	if opts == nil {
+		opts = DefaultOptions("Unknown Task")
+	}
+
+	sink := &slackSink{
+		tokens:  tokens,
+		channel: channel,
+		opts:    opts,
+	}
+	if opts.EphemeralUser != "" {
+		return &ephemeralSink{slack: sink, milestone: milestone{step: DefaultWebhookStep}}
+	}
+	return sink
+}
+
+// NewWithTokenProvider creates a new progress bar like New but gets the slack
+// token from tokens every time a message is sent.
+func NewWithTokenProvider(tokens TokenProvider, channel string, opts ...Option) (*Progress, error) {
+	o := buildOptions(opts)
+	return NewWithSink(NewSlackSinkWithTokenProvider(tokens, channel, o), o)
+}
+
+// refreshToken gets the token from s.tokens, if there is one, and creates a new
+// client if it has changed.
+func (s *slackSink) refreshToken(ctx context.Context) error {
+	if s.tokens == nil {
+		return nil
+	}
+
+	token, err := s.tokens.Token(ctx)
+	if err != nil {
+		return err
+	}
+	if s.client == nil || token != s.token {
+		s.client = newSlackClient(token, s.opts)
+		s.token = token
+	}
+	return nil
+}
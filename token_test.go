@@ -0,0 +1,46 @@
+package progress
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestTokenProvider(t *testing.T) {
+	var tokens []string
+	opts := DefaultOptions("deploy")
+	opts.HTTPClient = &http.Client{Transport: roundTripFunc(func(r *http.Request) (*http.Response, error) {
+		if err := r.ParseForm(); err != nil {
+			t.Errorf("Error parsing form: %s", err)
+		}
+		tokens = append(tokens, r.Form.Get("token"))
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Header:     http.Header{"Content-Type": {"application/json"}},
+			Body:       io.NopCloser(strings.NewReader(`{"ok":true,"channel":"C123","ts":"1.2"}`)),
+		}, nil
+	})}
+
+	token := "xoxb-1"
+	pbar, err := NewWithTokenProvider(TokenProviderFunc(func(ctx context.Context) (string, error) {
+		return token, nil
+	}), "C123", opts, WithMinInterval(0))
+	if err != nil {
+		t.Fatalf("Error creating progress bar: %s", err)
+	}
+
+	if err := pbar.Update(50); err != nil {
+		t.Fatalf("Error updating progress bar: %s", err)
+	}
+	token = "xoxb-2"
+	if err := pbar.Update(100); err != nil {
+		t.Fatalf("Error updating progress bar: %s", err)
+	}
+
+	want := []string{"xoxb-1", "xoxb-2"}
+	if len(tokens) != len(want) || tokens[0] != want[0] || tokens[1] != want[1] {
+		t.Errorf("Expected requests with %v, got %v", want, tokens)
+	}
+}
@@ -0,0 +1,42 @@
+//go:build go1.23
+
+package progress
+
+import "iter"
+
+// Track returns an iterator over the indexes and elements of s, like
+// slices.All, that sets the total to len(s) and advances p by one after the
+// body of the loop has run for each element, so wrapping a range loop is a
+// one line change:
+//
+//	for i, file := range progress.Track(pbar, files) {
+//
+// Errors updating the progress bar don't interrupt the loop.
+func Track[E any](p *Progress, s []E) iter.Seq2[int, E] {
+	return func(yield func(int, E) bool) {
+		if len(s) > 0 {
+			p.SetTotal(len(s))
+		}
+		for i, v := range s {
+			if !yield(i, v) {
+				return
+			}
+			p.Add(1)
+		}
+	}
+}
+
+// TrackSeq returns an iterator over the values of seq that advances p by one
+// after the body of the loop has run for each value. Set the total
+// beforehand if seq doesn't yield Options.TotalUnits values. Errors updating
+// the progress bar don't interrupt the loop.
+func TrackSeq[V any](p *Progress, seq iter.Seq[V]) iter.Seq[V] {
+	return func(yield func(V) bool) {
+		for v := range seq {
+			if !yield(v) {
+				return
+			}
+			p.Add(1)
+		}
+	}
+}
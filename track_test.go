@@ -0,0 +1,45 @@
+//go:build go1.23
+
+package progress_test
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/sfreiberg/progress"
+	"github.com/sfreiberg/progress/progresstest"
+)
+
+func TestTrack(t *testing.T) {
+	pbar, sink := progresstest.New(t)
+
+	files := []string{"a.csv", "b.csv", "c.csv", "d.csv"}
+	var seen []string
+	for i, file := range progress.Track(pbar, files) {
+		if i != len(seen) {
+			t.Errorf("Expected index %d, got %d", len(seen), i)
+		}
+		seen = append(seen, file)
+	}
+
+	if !slices.Equal(seen, files) {
+		t.Errorf("Expected %v, got %v", files, seen)
+	}
+	if last := sink.Last(); !last.Complete || last.Total != 4 {
+		t.Errorf("Expected the task to complete with a total of 4, got %d / %d", last.Pos, last.Total)
+	}
+}
+
+func TestTrackSeq(t *testing.T) {
+	pbar, sink := progresstest.New(t, progress.WithTotal(10))
+
+	for v := range progress.TrackSeq(pbar, slices.Values([]int{1, 2, 3, 4, 5})) {
+		if v == 4 {
+			break // The element the loop broke out of isn't counted
+		}
+	}
+
+	if last := sink.Last(); last.Pos != 3 {
+		t.Errorf("Expected a position of 3, got %d", last.Pos)
+	}
+}
@@ -0,0 +1,137 @@
+package progress
+
+import (
+	"io"
+	"sync"
+)
+
+// uploadReader sets the position of a progress bar to the number of bytes
+// read from a file that's being uploaded. Bytes that are read again after a
+// rewind or retry are only counted once.
+type uploadReader struct {
+	r     io.ReadSeeker
+	p     *Progress
+	start int64 // The offset of r when it was wrapped, which is position 0
+
+	mu   sync.Mutex
+	off  int64       // The current offset of r
+	read []byteRange // The ranges of r that have been read so far
+}
+
+// byteRange is the bytes from offset from up to, but not including, to.
+type byteRange struct {
+	from, to int64
+}
+
+// uploadReaderAt is an uploadReader for a file that also supports ReadAt,
+// which uploaders use to read parts concurrently.
+type uploadReaderAt struct {
+	uploadReader
+	ra io.ReaderAt
+}
+
+// NewUploadReader wraps r, e.g. an *os.File, for an uploader such as the AWS
+// SDK's S3 upload manager and sets the position to the number of bytes the
+// uploader has read. The total is set to the size of r. If r is an io.ReaderAt
+// so is the returned reader, so multipart uploads still read their parts
+// concurrently. Bytes that are read again, e.g. when an http.Client rewinds
+// the body to retry or follow a redirect, aren't counted twice. Errors
+// updating the progress bar don't interrupt the upload.
+func (p *Progress) NewUploadReader(r io.ReadSeeker) (io.ReadSeeker, error) {
+	pos, err := r.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return nil, err
+	}
+	end, err := r.Seek(0, io.SeekEnd)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := r.Seek(pos, io.SeekStart); err != nil {
+		return nil, err
+	}
+	if size := end - pos; size > 0 {
+		if err := p.SetTotal64(size); err != nil {
+			return nil, err
+		}
+	}
+
+	if ra, ok := r.(io.ReaderAt); ok {
+		return &uploadReaderAt{uploadReader: uploadReader{r: r, p: p, start: pos, off: pos}, ra: ra}, nil
+	}
+	return &uploadReader{r: r, p: p, start: pos, off: pos}, nil
+}
+
+func (ur *uploadReader) Read(b []byte) (int, error) {
+	n, err := ur.r.Read(b)
+
+	ur.mu.Lock()
+	from := ur.off
+	ur.off += int64(n)
+	ur.mu.Unlock()
+
+	if n > 0 {
+		ur.readRange(from, from+int64(n))
+	}
+	return n, err
+}
+
+func (ur *uploadReader) Seek(offset int64, whence int) (int64, error) {
+	off, err := ur.r.Seek(offset, whence)
+	if err == nil {
+		ur.mu.Lock()
+		ur.off = off
+		ur.mu.Unlock()
+	}
+	return off, err
+}
+
+func (ur *uploadReaderAt) ReadAt(b []byte, off int64) (int, error) {
+	n, err := ur.ra.ReadAt(b, off)
+	if n > 0 {
+		ur.readRange(off, off+int64(n))
+	}
+	return n, err
+}
+
+// readRange records that the bytes from up to to have been read and sets the
+// position to the number of distinct bytes read since start.
+func (ur *uploadReader) readRange(from, to int64) {
+	if to <= ur.start {
+		return
+	}
+
+	ur.mu.Lock()
+	r := byteRange{max(from, ur.start), to}
+	merged := ur.read[:0:0]
+	for _, read := range ur.read {
+		if read.to < r.from || r.to < read.from {
+			merged = append(merged, read)
+			continue
+		}
+		r = byteRange{min(r.from, read.from), max(r.to, read.to)}
+	}
+	ur.read = append(merged, r)
+
+	var pos int64
+	for _, read := range ur.read {
+		pos += read.to - read.from
+	}
+	ur.mu.Unlock()
+
+	ur.p.Update64(pos)
+}
+
+// BytesFunc returns a function that sets the position to the number of
+// bytes it's called with, for uploaders that report how much has been sent so
+// far, e.g. the ProgressFunc of a Google Cloud Storage writer:
+//
+//	w := bucket.Object(name).NewWriter(ctx)
+//	w.ProgressFunc = pbar.BytesFunc()
+//
+// Set the total to the size of the upload first. Errors updating the
+// progress bar are ignored.
+func (p *Progress) BytesFunc() func(n int64) {
+	return func(n int64) {
+		p.Update64(n)
+	}
+}
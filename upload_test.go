@@ -0,0 +1,74 @@
+package progress_test
+
+import (
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/sfreiberg/progress"
+	"github.com/sfreiberg/progress/progresstest"
+)
+
+func TestUploadReader(t *testing.T) {
+	pbar, sink := progresstest.New(t)
+
+	r, err := pbar.NewUploadReader(strings.NewReader(strings.Repeat("x", 1000)))
+	if err != nil {
+		t.Fatalf("Error creating reader: %s", err)
+	}
+
+	// Upload managers read parts concurrently with ReadAt
+	ra, ok := r.(io.ReaderAt)
+	if !ok {
+		t.Fatalf("Expected an io.ReaderAt")
+	}
+	part := make([]byte, 400)
+	if _, err := ra.ReadAt(part, 600); err != nil {
+		t.Fatalf("Error reading part: %s", err)
+	}
+	if last := sink.Last(); last.Total != 1000 || last.Pos != 400 {
+		t.Errorf("Expected 400 / 1000 bytes, got %d / %d", last.Pos, last.Total)
+	}
+
+	if _, err := io.CopyN(io.Discard, r, 600); err != nil {
+		t.Fatalf("Error reading: %s", err)
+	}
+	if !sink.Last().Complete {
+		t.Errorf("Expected the upload to complete")
+	}
+}
+
+func TestBytesFunc(t *testing.T) {
+	pbar, sink := progresstest.New(t, progress.WithTotal(2048))
+
+	report := pbar.BytesFunc()
+	report(512)
+	report(1024)
+
+	if last := sink.Last(); last.Pos != 1024 || last.Pct != 50 {
+		t.Errorf("Expected 1024 bytes, got %d", last.Pos)
+	}
+}
+
+func TestUploadReaderRewind(t *testing.T) {
+	pbar, sink := progresstest.New(t)
+
+	r, err := pbar.NewUploadReader(strings.NewReader(strings.Repeat("x", 1000)))
+	if err != nil {
+		t.Fatalf("Error creating reader: %s", err)
+	}
+
+	// An http.Client rewinds the body to retry a request
+	if _, err := io.CopyN(io.Discard, r, 300); err != nil {
+		t.Fatalf("Error reading: %s", err)
+	}
+	if _, err := r.Seek(0, io.SeekStart); err != nil {
+		t.Fatalf("Error seeking: %s", err)
+	}
+	if _, err := io.CopyN(io.Discard, r, 500); err != nil {
+		t.Fatalf("Error reading: %s", err)
+	}
+	if last := sink.Last(); last.Pos != 500 {
+		t.Errorf("Expected 500 bytes, got %d", last.Pos)
+	}
+}
@@ -0,0 +1,68 @@
+package progress
+
+import (
+	"errors"
+	"fmt"
+	"text/template"
+)
+
+// Validate reports options that would produce a broken progress bar, such as
+// a width or total that isn't positive, a negative interval or count, an
+// empty fill or a template that can't be parsed. Every problem found is
+// included in the error. It's called by New and NewWithSink.
+func (o *Options) Validate() error {
+	var errs []error
+
+	if o.Width <= 0 {
+		errs = append(errs, fmt.Errorf("Invalid width %d, it must be greater than 0", o.Width))
+	}
+	if o.TotalUnits <= 0 && o.Total64 <= 0 {
+		errs = append(errs, fmt.Errorf("Invalid total %d, it must be greater than 0", o.TotalUnits))
+	}
+	if o.Precision < 0 {
+		errs = append(errs, fmt.Errorf("Invalid precision %d, it can't be negative", o.Precision))
+	}
+	if o.Refresh < 0 {
+		errs = append(errs, fmt.Errorf("Invalid refresh %s, it can't be negative", o.Refresh))
+	}
+	// Stalls are checked every quarter of StallAfter, which has to be at
+	// least a nanosecond
+	if o.StallAfter < 0 || (o.StallAfter > 0 && o.StallAfter/4 == 0) {
+		errs = append(errs, fmt.Errorf("Invalid stall duration %s, it must be 0 or at least 4ns", o.StallAfter))
+	}
+	if o.History < 0 {
+		errs = append(errs, fmt.Errorf("Invalid history step %d, it can't be negative", o.History))
+	}
+	if o.LogLines < 0 {
+		errs = append(errs, fmt.Errorf("Invalid number of log lines %d, it can't be negative", o.LogLines))
+	}
+	if o.MaxLength < 0 {
+		errs = append(errs, fmt.Errorf("Invalid max length %d, it can't be negative", o.MaxLength))
+	}
+
+	if o.Theme != "" {
+		themesMu.RLock()
+		_, ok := themes[o.Theme]
+		themesMu.RUnlock()
+		if !ok {
+			errs = append(errs, fmt.Errorf("Unknown theme %q", o.Theme))
+		}
+	} else {
+		// Themes replace the fill characters
+		if o.Fill == "" && len(o.Fills) == 0 {
+			errs = append(errs, errors.New("Fill can't be empty"))
+		}
+		if o.Empty == "" {
+			errs = append(errs, errors.New("Empty can't be empty"))
+		}
+	}
+
+	templates := []struct{ name, src string }{{"Msg", o.Msg}, {"FailMsg", o.FailMsg}, {"CompleteMsg", o.CompleteMsg}}
+	for _, tmpl := range templates {
+		if _, err := template.New(tmpl.name).Funcs(funcs).Funcs(o.Funcs).Parse(tmpl.src); err != nil {
+			errs = append(errs, fmt.Errorf("Invalid %s template: %w", tmpl.name, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
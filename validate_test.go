@@ -0,0 +1,65 @@
+package progress_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sfreiberg/progress"
+)
+
+func TestValidate(t *testing.T) {
+	if err := progress.DefaultOptions("deploy").Validate(); err != nil {
+		t.Errorf("Expected the default options to be valid, got %s", err)
+	}
+
+	o := progress.DefaultOptions("deploy")
+	o.Width = 0
+	o.TotalUnits = 0
+	o.Fill = ""
+	o.Msg = "{{.Task"
+	err := o.Validate()
+	if err == nil {
+		t.Fatal("Expected the options to be invalid")
+	}
+	for _, want := range []string{"Invalid width 0", "Invalid total 0", "Fill can't be empty", "Invalid Msg template"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("Expected %q in %q", want, err)
+		}
+	}
+
+	if _, err := progress.NewWithSink(&memSink{}, progress.WithWidth(-1)); err == nil {
+		t.Error("Expected NewWithSink to validate the options")
+	}
+
+	o = progress.DefaultOptions("deploy")
+	o.Theme = "moons"
+	o.Fill = ""
+	if err := o.Validate(); err != nil {
+		t.Errorf("Expected the theme to provide the fill, got %s", err)
+	}
+}
+
+func TestValidateDurationsAndCounts(t *testing.T) {
+	o := progress.DefaultOptions("deploy")
+	o.Refresh = -time.Second
+	o.StallAfter = 3
+	o.History = -1
+	o.LogLines = -1
+	o.MaxLength = -1
+	err := o.Validate()
+	if err == nil {
+		t.Fatal("Expected the options to be invalid")
+	}
+	for _, want := range []string{"Invalid refresh -1s", "Invalid stall duration 3ns", "Invalid history step -1", "Invalid number of log lines -1", "Invalid max length -1"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("Expected %q in %q", want, err)
+		}
+	}
+
+	o = progress.DefaultOptions("deploy")
+	o.StallAfter = -time.Second
+	if err := o.Validate(); err == nil || !strings.Contains(err.Error(), "Invalid stall duration") {
+		t.Errorf("Expected a negative stall duration to be invalid, got %v", err)
+	}
+}
@@ -0,0 +1,70 @@
+package progress
+
+import (
+	"context"
+	"time"
+)
+
+// Watch updates the progress bar with the positions received from ch until ch
+// is closed or ctx is done, e.g. for a pipeline stage that already emits
+// counts. Positions that are waiting in ch are coalesced into the latest one
+// and updates are throttled by Options.MinInterval, but the last position is
+// always sent once ch is closed. Watch blocks so run it in its own goroutine.
+//
+// Errors don't stop Watch so the producer is never blocked. The first one is
+// returned when Watch returns, or ctx.Err() if ctx is done.
+func (p *Progress) Watch(ctx context.Context, ch <-chan int) error {
+	var pos int
+	var received bool
+	var firstErr error
+	for {
+		select {
+		case next, ok := <-ch:
+			if !ok {
+				if received {
+					if err := p.watchLast(ctx, pos); firstErr == nil {
+						firstErr = err
+					}
+				}
+				return firstErr
+			}
+			pos, received = latest(ch, next), true
+
+			if err := p.UpdateContext(ctx, pos); err != nil && firstErr == nil {
+				firstErr = err
+			}
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// latest returns the last of pos and the positions that are waiting in ch.
+func latest(ch <-chan int, pos int) int {
+	for {
+		select {
+		case next, ok := <-ch:
+			if !ok {
+				return pos // Watch sees that ch is closed next
+			}
+			pos = next
+		default:
+			return pos
+		}
+	}
+}
+
+// watchLast sends pos once Options.MinInterval has passed since the last
+// message so it isn't throttled.
+func (p *Progress) watchLast(ctx context.Context, pos int) error {
+	p.mu.Lock()
+	wait := p.Opts.MinInterval - time.Since(p.lastSent)
+	p.mu.Unlock()
+
+	if wait > 0 {
+		if err := sleep(ctx, wait); err != nil {
+			return err
+		}
+	}
+	return p.UpdateContext(ctx, pos)
+}
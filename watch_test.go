@@ -0,0 +1,44 @@
+package progress_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/sfreiberg/progress"
+	"github.com/sfreiberg/progress/progresstest"
+)
+
+func TestWatch(t *testing.T) {
+	sink := &progresstest.Sink{}
+	pbar, err := progress.NewWithSink(sink, progress.WithMinInterval(50*time.Millisecond))
+	if err != nil {
+		t.Fatalf("Error creating progress bar: %s", err)
+	}
+
+	ch := make(chan int)
+	done := make(chan error)
+	go func() { done <- pbar.Watch(context.Background(), ch) }()
+
+	for pos := 1; pos <= 42; pos++ {
+		ch <- pos
+	}
+	close(ch)
+
+	if err := <-done; err != nil {
+		t.Fatalf("Error watching: %s", err)
+	}
+	// The first position is posted, the rest are throttled until the last
+	if last := sink.Last(); last.Pos != 42 {
+		t.Errorf("Expected the last position to be sent, got %d", last.Pos)
+	}
+	if sent := len(sink.Posts()) + len(sink.Updates()); sent > 3 {
+		t.Errorf("Expected updates to be throttled, got %d messages", sent)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := pbar.Watch(ctx, make(chan int)); err != context.Canceled {
+		t.Errorf("Expected %v, got %v", context.Canceled, err)
+	}
+}
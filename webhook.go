@@ -0,0 +1,135 @@
+package progress
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+)
+
+// DefaultWebhookStep is how many percent the progress bar has to advance
+// before NewWebhook posts another message.
+const DefaultWebhookStep = 25
+
+// webhookSink posts progress messages to a slack incoming webhook. Webhooks
+// can't edit messages so a new message is posted every time the progress bar
+// crosses a milestone instead.
+type webhookSink struct {
+	url       string
+	milestone milestone
+}
+
+// NewWebhookSink creates a Sink that posts to a slack incoming webhook. Since
+// webhooks can't edit messages a new message is posted every step percent and
+// when the task completes.
+func NewWebhookSink(webhookURL string, step int) Sink {
+	if step <= 0 {
+		step = DefaultWebhookStep
+	}
+
+	return &webhookSink{
+		url:       webhookURL,
+		milestone: milestone{step: step},
+	}
+}
+
+// NewWebhook creates a new progress bar that posts to a slack incoming webhook.
+// A new message is posted every DefaultWebhookStep percent. Progress is created
+// with DefaultOptions customized by opts.
+func NewWebhook(webhookURL string, opts ...Option) (*Progress, error) {
+	return NewWithSink(NewWebhookSink(webhookURL, DefaultWebhookStep), opts...)
+}
+
+func (s *webhookSink) Post(ctx context.Context, msg *Message) (string, error) {
+	if err := s.send(ctx, msg); err != nil {
+		return "", err
+	}
+
+	// There's nothing to edit so any non empty id will do
+	return s.url, nil
+}
+
+func (s *webhookSink) Update(ctx context.Context, id string, msg *Message) error {
+	if !s.milestone.crossed(msg) {
+		return ErrSkipped
+	}
+
+	return s.send(ctx, msg)
+}
+
+func (s *webhookSink) send(ctx context.Context, msg *Message) error {
+	payload := map[string]string{"text": msg.Text}
+	if err := postJSON(ctx, s.url, payload, nil); err != nil {
+		return err
+	}
+
+	s.milestone.mark(msg)
+	return nil
+}
+
+// milestone keeps track of the last step a progress bar crossed for sinks
+// that can't edit messages.
+type milestone struct {
+	step int // Post a new message every step percent
+	last int // The last step that was posted
+}
+
+// crossed reports whether msg has reached a new step, completed or failed.
+func (m *milestone) crossed(msg *Message) bool {
+	return msg.Pct/m.step*m.step > m.last || msg.Complete || msg.Failed
+}
+
+// mark records msg as the last message that was posted.
+func (m *milestone) mark(msg *Message) {
+	m.last = msg.Pct / m.step * m.step
+}
+
+// postJSON sends v to url as json. If out is not nil the response body is
+// decoded into it.
+func postJSON(ctx context.Context, url string, v, out interface{}) error {
+	return sendJSON(ctx, http.MethodPost, url, nil, v, out)
+}
+
+// sendJSON sends v to url as json using method. Any headers in header are
+// added to the request. If out is not nil the response body is decoded into it.
+func sendJSON(ctx context.Context, method, url string, header http.Header, v, out interface{}) error {
+	body, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	return sendBody(ctx, method, url, header, body, out)
+}
+
+// sendBody sends the json document body to url using method. Any headers in
+// header are added to the request. If out is not nil the response body is
+// decoded into it.
+func sendBody(ctx context.Context, method, url string, header http.Header, body []byte, out interface{}) error {
+	req, err := http.NewRequest(method, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	for k, vals := range header {
+		req.Header[k] = vals
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := http.DefaultClient.Do(req.WithContext(ctx))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusTooManyRequests {
+		return &RateLimitedError{RetryAfter: retryAfter(resp.Header)}
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return &StatusError{Code: resp.StatusCode, Status: resp.Status, Host: req.URL.Host}
+	}
+
+	if out == nil {
+		return nil
+	}
+
+	return json.NewDecoder(resp.Body).Decode(out)
+}
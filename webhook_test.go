@@ -0,0 +1,53 @@
+package progress_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/sfreiberg/progress"
+)
+
+func TestWebhook(t *testing.T) {
+	var (
+		mu    sync.Mutex
+		texts []string
+	)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var payload struct{ Text string }
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			t.Errorf("Error decoding webhook payload: %s", err)
+		}
+
+		mu.Lock()
+		texts = append(texts, payload.Text)
+		mu.Unlock()
+	}))
+	defer srv.Close()
+
+	var sends int
+	onSend := func(ctx context.Context, msg *progress.Message, took time.Duration, err error) { sends++ }
+	pbar, err := progress.NewWebhook(srv.URL, progress.WithMinInterval(0), progress.WithSendHook(onSend))
+	if err != nil {
+		t.Fatalf("Error creating progress bar: %s", err)
+	}
+	for i := 0; i <= pbar.Opts.TotalUnits; i++ {
+		if err := pbar.Update(i); err != nil {
+			t.Fatalf("Error updating progress bar: %s", err)
+		}
+	}
+
+	// The first update plus 25, 50, 75 and 100 percent
+	if len(texts) != 5 {
+		t.Fatalf("Expected 5 webhook messages, got %d", len(texts))
+	}
+	// Updates between milestones are skipped, not sent
+	if sends != 5 {
+		t.Errorf("Expected 5 messages to be reported as sent, got %d", sends)
+	}
+}